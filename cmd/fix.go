@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/app"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+	"github.com/spf13/cobra"
+)
+
+// fixCmd represents the fix command
+var fixCmd = &cobra.Command{
+	Use:   "fix [paths...]",
+	Short: "Fix NestJS module issues, or undo the last fix",
+	Long: `Fix NestJS module issues, like import-lint --fix.
+
+Files are written atomically, keeping their mode, line endings and byte order
+mark. Every run is recorded in a journal under the user cache directory, so
+the last run can be undone as long as the fixed files have not changed since.
+
+Inside a git work tree, files with uncommitted changes are left unchanged
+unless --allow-dirty is passed. --commit commits the fixed files and
+--patch-file writes the changes as a patch, both with a generated message
+listing the removed and added imports.
+
+Examples:
+  # Fix unused imports
+  nestjs-module-lint fix src/
+
+  # Fix and commit the result
+  nestjs-module-lint fix --commit src/
+
+  # Restore the files changed by the last fix
+  nestjs-module-lint fix --undo`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if undoMode {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if undoMode {
+			runUndo()
+			return
+		}
+
+		if err := fixSelector.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		runFix(args)
+	},
+}
+
+var undoMode bool
+
+func init() {
+	rootCmd.AddCommand(fixCmd)
+
+	fixCmd.Flags().BoolVar(&undoMode, "undo", false, "Restore the files changed by the last fix run")
+	addSelectorFlags(fixCmd)
+	addGitFlags(fixCmd)
+	fixCmd.Flags().BoolVar(&ofJson, "json", false, "Output the fix results in JSON format")
+	fixCmd.Flags().StringSliceVar(&moduleDecorators, "module-decorator", nil, "Custom decorator that wraps @Module() metadata (repeatable)")
+}
+
+// runUndo restores the files changed by the last fix run
+func runUndo() {
+	paths, err := app.UndoLastFix()
+	if errors.Is(err, fixing.ErrNoJournal) {
+		fmt.Println("✓ No fix run to undo")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error undoing fix: %v\n", err)
+		os.Exit(2)
+	}
+
+	for _, path := range paths {
+		fmt.Printf("✓ Restored %s\n", path)
+	}
+	fmt.Printf("✓ Restored %s\n", reporting.Pluralize(len(paths), "file"))
+}
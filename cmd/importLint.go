@@ -8,8 +8,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
 	"github.com/evanrichards/nestjs-module-lint/internal/app"
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
 	"github.com/spf13/cobra"
 )
 
@@ -20,8 +25,8 @@ var importLintCmd = &cobra.Command{
 	Long: `Analyze NestJS modules for unused imports in @Module() decorators.
 
 Exit codes:
-  0 - No unused imports found (or --exit-zero flag used)
-  1 - Unused imports found
+  0 - No findings (or --exit-zero flag used)
+  1 - Findings reported
   2 - Execution error (invalid path, parsing error, etc.)
 
 Examples:
@@ -31,6 +36,42 @@ Examples:
   # Automatically fix unused imports
   nestjs-module-lint import-lint --fix src/
 
+  # Review the fixes as a unified diff without writing them
+  nestjs-module-lint import-lint --fix --dry-run src/
+
+  # Review each removal before applying it
+  nestjs-module-lint import-lint --fix --interactive src/
+
+  # Fix and report what changed in each file as JSON
+  nestjs-module-lint import-lint --fix --json src/
+
+  # Only remove LegacyAuthModule, and only in one team's directory
+  nestjs-module-lint import-lint --fix --only-import LegacyAuthModule --paths 'src/team-a/**' src/
+
+  # List the planned edits as JSON
+  nestjs-module-lint import-lint --fix --dry-run --json src/
+
+  # SARIF log for code scanning, with the fix of each finding
+  nestjs-module-lint import-lint --format sarif src/ > results.sarif
+
+  # JUnit XML for CI test reports
+  nestjs-module-lint import-lint --format junit src/ > module-lint.xml
+
+  # Explain the verdict on every import, with the code of each
+  nestjs-module-lint import-lint --verbose src/
+
+  # Markdown summary for a pull request comment
+  nestjs-module-lint import-lint --format markdown src/ > module-lint.md
+
+  # Render a custom report, e.g. CSV, with a Go text/template
+  nestjs-module-lint import-lint --format template --template findings.csv.tmpl src/
+
+  # Text in the log, SARIF and JSON files from a single analysis
+  nestjs-module-lint import-lint --format text --format sarif=results.sarif --format json=report.json src/
+
+  # Inline pull request annotations in GitHub Actions
+  nestjs-module-lint import-lint --format github src/
+
   # CI/CD usage with clear pass/fail
   nestjs-module-lint import-lint --check src/
 
@@ -38,67 +79,60 @@ Examples:
   nestjs-module-lint import-lint --exit-zero --quiet src/`,
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		// Handle fix mode separately
-		if fixMode {
-			for _, arg := range args {
-				// Validate argument
-				if strings.TrimSpace(arg) == "" {
-					fmt.Fprintf(os.Stderr, "Error: empty path provided\n")
-					os.Exit(2)
-				}
-
-				err := app.FixWorkflow(arg)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error fixing '%s': %v\n", arg, err)
+		if err := fixSelector.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		outputs, err := parseOutputs()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		if fixMode || dryRun || diffMode {
+			// Fixes report what changed as text or JSON on stdout
+			for _, out := range outputs {
+				if out.path != "" || (out.format != reporting.FormatText && out.format != reporting.FormatJSON) {
+					fmt.Fprintf(os.Stderr, "Error: --format %s cannot be combined with --fix\n", out.spec())
 					os.Exit(2)
 				}
 			}
-			return
-		}
-
-		// Normal analysis mode
-		var allReports []*app.ModuleReport
-
-		for _, arg := range args {
-			// Validate argument
-			if strings.TrimSpace(arg) == "" {
-				fmt.Fprintf(os.Stderr, "Error: empty path provided\n")
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Error: --verbose cannot be combined with --fix\n")
 				os.Exit(2)
 			}
+			ofJson = outputs[0].format == reporting.FormatJSON
+		}
 
-			reports, err := app.AnalyzePath(arg)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error analyzing '%s': %v\n", arg, err)
-				os.Exit(2) // Exit code 2 for execution errors
+		// Dry runs plan the fixes without writing them
+		if dryRun || diffMode {
+			if allowDirty || commitFixes || patchFile != "" {
+				fmt.Fprintf(os.Stderr, "Error: --allow-dirty, --commit and --patch-file cannot be combined with --dry-run\n")
+				os.Exit(2)
 			}
-			allReports = append(allReports, reports...)
+			runDryRun(args)
+			return
 		}
 
-		// Output results based on format
-		if ofJson {
-			d, _ := json.Marshal(allReports)
-			fmt.Println(string(d))
-		} else if !quiet {
-			// Text output (default)
-			for _, report := range allReports {
-				fmt.Println(app.PrettyPrintModuleReport(report))
-			}
+		if interactive && !fixMode {
+			fmt.Fprintf(os.Stderr, "Error: --interactive requires --fix\n")
+			os.Exit(2)
+		}
+		if (allowDirty || commitFixes || patchFile != "") && !fixMode {
+			fmt.Fprintf(os.Stderr, "Error: --allow-dirty, --commit and --patch-file require --fix\n")
+			os.Exit(2)
+		}
 
+		// Handle fix mode separately
+		if fixMode {
 			if checkMode {
-				if len(allReports) > 0 {
-					fmt.Printf("✗ Found %d modules with unused imports\n", len(allReports))
-				} else {
-					fmt.Println("✓ No unused imports found")
-				}
-			} else {
-				fmt.Printf("Total number of modules with unused imports: %d\n", len(allReports))
+				fmt.Fprintf(os.Stderr, "Error: --fix can only be combined with --check together with --dry-run\n")
+				os.Exit(2)
 			}
+			runFix(args)
+			return
 		}
 
-		// Determine exit code
-		if len(allReports) > 0 && !exitZero {
-			os.Exit(1) // Exit code 1 for linting failures
-		}
+		runReport(args, outputs)
 	},
 }
 
@@ -107,7 +141,19 @@ var ofText bool
 var exitZero bool
 var checkMode bool
 var quiet bool
+var verbose bool
 var fixMode bool
+var dryRun bool
+var diffMode bool
+var interactive bool
+var outputFormats []string
+var markdownMaxSize int
+var templateFile string
+var fixSelector fixing.Selector
+var allowDirty bool
+var commitFixes bool
+var patchFile string
+var moduleDecorators []string
 
 func init() {
 	rootCmd.AddCommand(importLintCmd)
@@ -115,6 +161,10 @@ func init() {
 	// Output format flags
 	importLintCmd.Flags().BoolVar(&ofJson, "json", false, "Output in JSON format")
 	importLintCmd.Flags().BoolVar(&ofText, "text", false, "Output in text format")
+	importLintCmd.Flags().StringArrayVar(&outputFormats, "format", nil, "Output format: text, json, sarif, junit, checkstyle, github, gitlab, markdown or template, written to stdout or to a file with name=path (repeatable, default text)")
+	importLintCmd.Flags().StringVar(&templateFile, "template", "", "Go text/template file rendered by --format template")
+	importLintCmd.Flags().BoolVar(&verbose, "verbose", false, "Explain the verdict on every import in text output, with code frames")
+	importLintCmd.Flags().IntVar(&markdownMaxSize, "markdown-max-size", reporting.DefaultMarkdownMaxSize, fmt.Sprintf("Maximum size in bytes of --format markdown output, at least %d, or 0 for no limit", reporting.MinMarkdownMaxSize))
 
 	// CI/CD flags
 	importLintCmd.Flags().BoolVar(&checkMode, "check", false, "Check mode with pass/fail output (good for CI)")
@@ -123,8 +173,271 @@ func init() {
 
 	// Fix flags
 	importLintCmd.Flags().BoolVar(&fixMode, "fix", false, "Automatically remove unused imports")
+	importLintCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the fixes as a unified diff (or JSON edits with --json) without writing files")
+	importLintCmd.Flags().BoolVar(&diffMode, "diff", false, "Alias for --dry-run")
+	importLintCmd.Flags().BoolVar(&interactive, "interactive", false, "Review each unused import before --fix removes it")
+
+	addSelectorFlags(importLintCmd)
+	addGitFlags(importLintCmd)
+
+	// Parsing flags
+	importLintCmd.Flags().StringSliceVar(&moduleDecorators, "module-decorator", nil, "Custom decorator that wraps @Module() metadata (repeatable)")
 
 	importLintCmd.MarkFlagsMutuallyExclusive("json", "text")
-	importLintCmd.MarkFlagsMutuallyExclusive("fix", "json")
-	importLintCmd.MarkFlagsMutuallyExclusive("fix", "check")
+	importLintCmd.MarkFlagsMutuallyExclusive("format", "json")
+	importLintCmd.MarkFlagsMutuallyExclusive("format", "text")
+	importLintCmd.MarkFlagsMutuallyExclusive("interactive", "dry-run")
+	importLintCmd.MarkFlagsMutuallyExclusive("interactive", "diff")
+	for _, flag := range []string{"commit", "patch-file"} {
+		importLintCmd.MarkFlagsMutuallyExclusive(flag, "dry-run")
+		importLintCmd.MarkFlagsMutuallyExclusive(flag, "diff")
+	}
+}
+
+// runReport analyzes every path once and writes the results in each output
+// format, to stdout or to the output's file. Fixes are planned once for all
+// formats that describe them. It exits with code 1 when issues are found.
+func runReport(args []string, outputs []output) {
+	started := time.Now()
+	var results []*analysis.ModuleAnalysisResult
+	for _, arg := range args {
+		// Validate argument
+		if strings.TrimSpace(arg) == "" {
+			fmt.Fprintf(os.Stderr, "Error: empty path provided\n")
+			os.Exit(2)
+		}
+
+		argResults, err := app.AnalyzeResults(arg, analysisOptions())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error analyzing '%s': %v\n", arg, err)
+			os.Exit(2) // Exit code 2 for execution errors
+		}
+		results = append(results, argResults...)
+	}
+
+	workingDirectory, _ := os.Getwd()
+	formatter := reporting.NewFormatter().
+		WithMarkdownMaxSize(markdownMaxSize).
+		WithVerbose(verbose).
+		WithRun(reporting.ReportRun{
+			Paths:            args,
+			WorkingDirectory: workingDirectory,
+			StartedAt:        started.UTC().Format(time.RFC3339),
+			DurationMS:       time.Since(started).Milliseconds(),
+		})
+	if templateFile != "" {
+		tmpl, err := reporting.ParseTemplate(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading template '%s': %v\n", templateFile, err)
+			os.Exit(2)
+		}
+		formatter.WithTemplate(tmpl)
+	}
+	for _, out := range outputs {
+		if out.format.IncludesFixes() {
+			fixes, err := app.PlanFindingFixes(results, analysisOptions())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error planning fixes: %v\n", err)
+				os.Exit(2)
+			}
+			formatter.WithFixes(fixes)
+			break
+		}
+	}
+
+	for _, out := range outputs {
+		var content string
+		if out.format == reporting.FormatText && !verbose {
+			content = textReport(results)
+		} else {
+			// Verbose text is colored on terminals only
+			formatter.WithColor(out.format == reporting.FormatText && out.path == "" && colorStdout())
+			formatted, err := formatter.Format(results, out.format)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting results: %v\n", err)
+				os.Exit(2)
+			}
+			content = strings.TrimSuffix(formatted, "\n") + "\n"
+		}
+
+		if out.path == "" {
+			if !quiet || out.format != reporting.FormatText {
+				fmt.Print(content)
+			}
+			continue
+		}
+		if err := filesystem.WriteFileAtomic(out.path, []byte(content)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s report to '%s': %v\n", out.format, out.path, err)
+			os.Exit(2)
+		}
+	}
+
+	// Determine exit code
+	for _, result := range results {
+		if result.HasFindings() && !exitZero {
+			os.Exit(1) // Exit code 1 for linting failures
+		}
+	}
+}
+
+// textReport formats the modules with findings as human-readable text,
+// followed by the findings of each rule or, in check mode, a pass or fail
+// line
+func textReport(results []*analysis.ModuleAnalysisResult) string {
+	var builder strings.Builder
+	for _, report := range app.ModuleReports(results) {
+		builder.WriteString(app.PrettyPrintModuleReport(report) + "\n")
+	}
+	builder.WriteString(reporting.NewFormatter().GetSummary(results, checkMode) + "\n")
+	return builder.String()
+}
+
+// runDryRun prints the fixes --fix would make, leaving the files untouched.
+// It exits with code 1 when files would change.
+func runDryRun(args []string) {
+	plan := &fixing.Plan{}
+	for _, arg := range args {
+		// Validate argument
+		if strings.TrimSpace(arg) == "" {
+			fmt.Fprintf(os.Stderr, "Error: empty path provided\n")
+			os.Exit(2)
+		}
+
+		argPlan, err := app.PlanFixes(arg, analysisOptions())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fixing '%s': %v\n", arg, err)
+			os.Exit(2)
+		}
+		plan.Files = append(plan.Files, argPlan.Files...)
+		plan.Skipped = append(plan.Skipped, argPlan.Skipped...)
+		plan.RolledBack = append(plan.RolledBack, argPlan.RolledBack...)
+	}
+
+	if ofJson {
+		d, _ := json.Marshal(plan.Edits())
+		fmt.Println(string(d))
+	} else if !quiet {
+		fmt.Print(plan.Diff())
+		for _, skipped := range plan.Skipped {
+			fmt.Printf("⚠ Skipped %s\n", skipped)
+		}
+		for _, rolledBack := range plan.RolledBack {
+			fmt.Printf("✗ Rolled back %s\n", rolledBack)
+		}
+		if plan.HasChanges() {
+			fmt.Printf("✗ %s would be fixed\n", reporting.Pluralize(len(plan.Files), "file"))
+		} else {
+			fmt.Println("✓ No unused imports found - nothing to fix")
+		}
+	}
+
+	if plan.HasChanges() && !exitZero {
+		os.Exit(1)
+	}
+}
+
+// runFix fixes every path as one run, which fix --undo reverts as a whole,
+// and prints what changed in each file. It exits with code 1 when a file
+// could not be fixed.
+func runFix(args []string) {
+	for _, arg := range args {
+		// Validate argument
+		if strings.TrimSpace(arg) == "" {
+			fmt.Fprintf(os.Stderr, "Error: empty path provided\n")
+			os.Exit(2)
+		}
+	}
+
+	var results []*fixing.FixResult
+	var err error
+	if interactive {
+		// Keep prompts out of JSON output
+		prompts := os.Stdout
+		if ofJson {
+			prompts = os.Stderr
+		}
+		results, err = app.FixInteractive(args, analysisOptions(), os.Stdin, prompts)
+	} else {
+		results, err = app.FixWorkflow(args, analysisOptions())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fixing '%s': %v\n", strings.Join(args, "', '"), err)
+		os.Exit(2)
+	}
+
+	fixed := 0
+	failed := false
+	for _, result := range results {
+		if result.Fixed {
+			fixed++
+		}
+		if result.Error != "" {
+			failed = true
+		}
+	}
+
+	if patchFile != "" {
+		if err := app.WritePatch(patchFile, results); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing patch file: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	committed := false
+	if commitFixes {
+		var err error
+		committed, err = app.CommitFixes(results)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error committing fixes: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if ofJson {
+		d, _ := json.Marshal(results)
+		fmt.Println(string(d))
+	} else if !quiet {
+		for _, result := range results {
+			fmt.Print(app.PrettyPrintFixResult(result))
+		}
+		if fixed > 0 {
+			fmt.Printf("✓ Successfully fixed %s\n", reporting.Pluralize(fixed, "file"))
+		} else {
+			fmt.Println("✓ No unused imports found - nothing to fix")
+		}
+		if committed {
+			fmt.Printf("✓ Committed %s\n", reporting.Pluralize(fixed, "file"))
+		}
+		if patchFile != "" {
+			fmt.Printf("✓ Wrote patch to %s\n", patchFile)
+		}
+	}
+
+	if failed && !exitZero {
+		os.Exit(1)
+	}
+}
+
+// addSelectorFlags adds the flags limiting which findings a fix applies to
+func addSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&fixSelector.Modules, "only-module", nil, "Only fix findings of these modules (repeatable)")
+	cmd.Flags().StringSliceVar(&fixSelector.Imports, "only-import", nil, "Only fix findings about these imports, entities or added modules (repeatable)")
+	cmd.Flags().StringSliceVar(&fixSelector.Rules, "only-rule", nil, "Only fix findings of these rules, e.g. unused-import (repeatable)")
+	cmd.Flags().StringSliceVar(&fixSelector.Paths, "paths", nil, "Only fix module files matching these globs, e.g. 'src/users/**' (repeatable)")
+	cmd.Flags().StringSliceVar(&fixSelector.Types, "fix-type", nil, "Only apply these kinds of fixes: remove, add (repeatable)")
+}
+
+// addGitFlags adds the flags controlling how fixes interact with git
+func addGitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, "Fix files with uncommitted changes in a git work tree")
+	cmd.Flags().BoolVar(&commitFixes, "commit", false, "Commit the fixed files with a generated message")
+	cmd.Flags().StringVar(&patchFile, "patch-file", "", "Also write the fixes as a patch file")
+}
+
+// analysisOptions builds the app options from the command line flags
+func analysisOptions() app.Options {
+	return app.Options{
+		ModuleDecorators: moduleDecorators,
+		FixSelector:      fixSelector,
+		AllowDirty:       allowDirty,
+	}
 }
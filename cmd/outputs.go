@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+// outputFormatNames lists the formats --format accepts
+var outputFormatNames = []reporting.OutputFormat{
+	reporting.FormatText,
+	reporting.FormatJSON,
+	reporting.FormatSARIF,
+	reporting.FormatJUnit,
+	reporting.FormatCheckstyle,
+	reporting.FormatGitHub,
+	reporting.FormatGitLab,
+	reporting.FormatMarkdown,
+	reporting.FormatTemplate,
+}
+
+// output is a report format and the file it is written to, or stdout for an
+// empty path
+type output struct {
+	format reporting.OutputFormat
+	path   string
+}
+
+// spec returns the output as given to --format
+func (o output) spec() string {
+	if o.path == "" {
+		return string(o.format)
+	}
+	return fmt.Sprintf("%s=%s", o.format, o.path)
+}
+
+// parseOutputs parses the --format values, each a format name optionally
+// followed by =path. Without --format, text or --json output goes to stdout.
+func parseOutputs() ([]output, error) {
+	if markdownMaxSize > 0 && markdownMaxSize < reporting.MinMarkdownMaxSize {
+		return nil, fmt.Errorf("--markdown-max-size must be 0 or at least %d", reporting.MinMarkdownMaxSize)
+	}
+	if len(outputFormats) == 0 {
+		if ofJson {
+			return []output{{format: reporting.FormatJSON}}, nil
+		}
+		if templateFile != "" {
+			return nil, fmt.Errorf("--template requires --format template")
+		}
+		return []output{{format: reporting.FormatText}}, nil
+	}
+
+	var outputs []output
+	stdout := ""
+	paths := make(map[string]bool)
+	hasTemplate := false
+	for _, value := range outputFormats {
+		name, path, hasPath := strings.Cut(value, "=")
+		format := reporting.OutputFormat(name)
+		if !isOutputFormat(format) {
+			return nil, fmt.Errorf("unknown format %q, expected %s", name, formatList())
+		}
+		if hasPath && path == "" {
+			return nil, fmt.Errorf("--format %s= needs a file path", name)
+		}
+
+		if path == "" {
+			if stdout != "" {
+				return nil, fmt.Errorf("--format %s and --format %s both write to stdout, give one a file with name=path", stdout, name)
+			}
+			stdout = name
+		} else {
+			// Spellings of the same file, like r.json and ./r.json, conflict
+			key, err := filepath.Abs(path)
+			if err != nil {
+				key = filepath.Clean(path)
+			}
+			if paths[key] {
+				return nil, fmt.Errorf("more than one format writes to %s", path)
+			}
+			paths[key] = true
+		}
+		hasTemplate = hasTemplate || format == reporting.FormatTemplate
+		outputs = append(outputs, output{format: format, path: path})
+	}
+
+	if hasTemplate != (templateFile != "") {
+		return nil, fmt.Errorf("--format template and --template must be used together")
+	}
+	return outputs, nil
+}
+
+// isOutputFormat reports whether --format accepts format
+func isOutputFormat(format reporting.OutputFormat) bool {
+	for _, name := range outputFormatNames {
+		if name == format {
+			return true
+		}
+	}
+	return false
+}
+
+// formatList lists the format names for messages, e.g. "text, json or sarif"
+func formatList() string {
+	names := make([]string, len(outputFormatNames))
+	for i, name := range outputFormatNames {
+		names[i] = string(name)
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// colorStdout reports whether stdout is a terminal that text output may be
+// colored on, unless the NO_COLOR environment variable is set
+func colorStdout() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
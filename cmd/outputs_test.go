@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+// withOutputFlags sets the flags parseOutputs reads for one test
+func withOutputFlags(t *testing.T, formats []string, json bool, template string) {
+	t.Helper()
+	savedFormats, savedJson, savedTemplate, savedMaxSize := outputFormats, ofJson, templateFile, markdownMaxSize
+	t.Cleanup(func() {
+		outputFormats, ofJson, templateFile, markdownMaxSize = savedFormats, savedJson, savedTemplate, savedMaxSize
+	})
+	outputFormats, ofJson, templateFile, markdownMaxSize = formats, json, template, reporting.DefaultMarkdownMaxSize
+}
+
+func TestParseOutputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		formats  []string
+		json     bool
+		template string
+		expected []output
+	}{
+		{
+			name:     "Default text",
+			expected: []output{{format: reporting.FormatText}},
+		},
+		{
+			name:     "JSON flag",
+			json:     true,
+			expected: []output{{format: reporting.FormatJSON}},
+		},
+		{
+			name:    "Stdout and files",
+			formats: []string{"text", "sarif=results.sarif", "json=report.json"},
+			expected: []output{
+				{format: reporting.FormatText},
+				{format: reporting.FormatSARIF, path: "results.sarif"},
+				{format: reporting.FormatJSON, path: "report.json"},
+			},
+		},
+		{
+			name:     "Template",
+			formats:  []string{"template=report.html"},
+			template: "report.tmpl",
+			expected: []output{{format: reporting.FormatTemplate, path: "report.html"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withOutputFlags(t, tt.formats, tt.json, tt.template)
+			outputs, err := parseOutputs()
+			if err != nil {
+				t.Fatalf("parseOutputs failed: %v", err)
+			}
+			if len(outputs) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, outputs)
+			}
+			for i := range outputs {
+				if outputs[i] != tt.expected[i] {
+					t.Errorf("Expected output %d to be %v, got %v", i, tt.expected[i], outputs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseOutputs_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		formats  []string
+		template string
+		expected string
+	}{
+		{
+			name:     "Two formats on stdout",
+			formats:  []string{"text", "json"},
+			expected: "--format text and --format json both write to stdout",
+		},
+		{
+			name:     "Empty path",
+			formats:  []string{"sarif="},
+			expected: "--format sarif= needs a file path",
+		},
+		{
+			name:     "Unknown format",
+			formats:  []string{"yaml"},
+			expected: `unknown format "yaml"`,
+		},
+		{
+			name:     "Same file",
+			formats:  []string{"json=r.json", "sarif=r.json"},
+			expected: "more than one format writes to r.json",
+		},
+		{
+			name:     "Same file spelled differently",
+			formats:  []string{"json=r.json", "sarif=./out/../r.json"},
+			expected: "more than one format writes to ./out/../r.json",
+		},
+		{
+			name:     "Template format without --template",
+			formats:  []string{"template=report.html"},
+			expected: "--format template and --template must be used together",
+		},
+		{
+			name:     "--template without template format",
+			formats:  []string{"text"},
+			template: "report.tmpl",
+			expected: "--format template and --template must be used together",
+		},
+		{
+			name:     "--template without --format",
+			template: "report.tmpl",
+			expected: "--template requires --format template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withOutputFlags(t, tt.formats, false, tt.template)
+			if _, err := parseOutputs(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseOutputs_MarkdownMaxSize(t *testing.T) {
+	for size, valid := range map[int]bool{0: true, 50: false, reporting.MinMarkdownMaxSize: true} {
+		withOutputFlags(t, []string{"markdown"}, false, "")
+		markdownMaxSize = size
+		if _, err := parseOutputs(); (err == nil) != valid {
+			t.Errorf("Expected --markdown-max-size %d valid: %v, got error %v", size, valid, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/app"
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report --html <file> [paths...]",
+	Short: "Write an HTML report of the module graph",
+	Long: `Write an HTML report of the module graph.
+
+The report is a single HTML file with its data, styles and script inline, so
+it opens from disk without network access. It draws every module with the
+modules it imports; clicking a module shows its imports, providers, exports
+and findings. Unused imports and imports that form a cycle are highlighted.
+
+Examples:
+  # Write the report of a project
+  nestjs-module-lint report --html module-graph.html src/`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var results []*analysis.ModuleAnalysisResult
+		for _, arg := range args {
+			// Validate argument
+			if strings.TrimSpace(arg) == "" {
+				fmt.Fprintf(os.Stderr, "Error: empty path provided\n")
+				os.Exit(2)
+			}
+
+			argResults, err := app.AnalyzeModuleGraph(arg, analysisOptions())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error analyzing '%s': %v\n", arg, err)
+				os.Exit(2)
+			}
+			results = append(results, argResults...)
+		}
+
+		page, err := reporting.NewFormatter().HTML(results)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting report: %v\n", err)
+			os.Exit(2)
+		}
+		if err := filesystem.WriteFileAtomic(htmlReportFile, []byte(page)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Printf("✓ Wrote the report of %s to %s\n", reporting.Pluralize(len(results), "module"), htmlReportFile)
+	},
+}
+
+var htmlReportFile string
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&htmlReportFile, "html", "", "Write the HTML report to this file")
+	reportCmd.Flags().StringSliceVar(&moduleDecorators, "module-decorator", nil, "Custom decorator that wraps @Module() metadata (repeatable)")
+	_ = reportCmd.MarkFlagRequired("html")
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of JSON reports",
+	Long: `Print the JSON Schema document that import-lint --format json output follows.
+
+Reports carry the schema version in schema_version. Fields are added in minor
+versions and renamed or removed in major versions.
+
+Examples:
+  # Save the schema to validate reports against
+  nestjs-module-lint schema > nestjs-module-lint.schema.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Print(reporting.JSONSchema())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -1,7 +1,9 @@
 package analysis
 
 import (
+	"fmt"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +16,9 @@ type moduleImportData struct {
 	name    string
 	path    string
 	exports []string
+	// external is set for modules that do not resolve to a project file, such
+	// as package imports; they cannot be analyzed and are treated as used
+	external bool
 }
 
 // providerData holds information about a provider/controller
@@ -30,6 +35,11 @@ type Analyzer struct {
 	ignoreDetector   IgnoreDetector
 	reExportDetector ReExportDetector
 	options          AnalysisOptions
+
+	// exportIndex maps provider names to the project modules exporting them,
+	// built on first use
+	exportIndex     map[string][]exportedProvider
+	exportIndexOnce sync.Once
 }
 
 // NewAnalyzer creates a new module analyzer with the given dependencies
@@ -57,12 +67,6 @@ func (a *Analyzer) AnalyzeFile(filePath string) ([]*ModuleAnalysisResult, error)
 		return nil, err
 	}
 
-	// Parse module information
-	_, err = a.parser.ParseModuleInfo(absPath)
-	if err != nil {
-		return nil, err
-	}
-
 	// Check if file should be ignored
 	if a.options.EnableIgnores {
 		sourceCode, err := filesystem.ReadFile(absPath)
@@ -90,24 +94,113 @@ func (a *Analyzer) AnalyzeFile(filePath string) ([]*ModuleAnalysisResult, error)
 		return nil, err
 	}
 
+	injectionsByModule, err := a.parser.GetProviderInjectionsByModule(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	featuresByModule := make(map[string][]FeatureEntity)
+	if a.options.EnableFeatureEntities {
+		featuresByModule, err = a.parser.GetFeatureEntitiesByModule(absPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Convert to relative path for output
 	relativePath, err := filepath.Rel(a.options.WorkingDirectory, absPath)
 	if err != nil {
 		relativePath = absPath
 	}
 
+	referencesByModule := make(map[string][]string)
+	if a.options.EnableMissingImports {
+		referencesByModule, err = a.parser.GetImportReferencesByModule(absPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	queuesByModule := make(map[string][]string)
+	if a.options.EnableQueues {
+		queuesByModule, err = a.parser.GetQueueRegistrationsByModule(absPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	locationsByModule, err := a.parser.GetModuleLocations(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Modules that only register ORM features, or whose providers inject
+	// queues, have no imports to analyze
+	moduleNames := make(map[string]bool)
+	for moduleName := range importsByModule {
+		moduleNames[moduleName] = true
+	}
+	for moduleName := range featuresByModule {
+		moduleNames[moduleName] = true
+	}
+	if a.options.EnableQueues || a.options.EnableMissingImports {
+		for moduleName := range providersByModule {
+			moduleNames[moduleName] = true
+		}
+	}
+
 	var results []*ModuleAnalysisResult
-	for moduleName, imports := range importsByModule {
+	for moduleName := range moduleNames {
 		result := a.analyzeModuleImports(
 			moduleName,
-			imports,
+			importsByModule[moduleName],
 			exportsByModule[moduleName],
 			providersByModule[moduleName],
+			injectionsByModule[moduleName],
 			relativePath,
 			absPath,
 		)
 
-		if result != nil && len(result.UnusedImports) > 0 {
+		if a.options.EnableFeatureEntities {
+			result.Findings = append(result.Findings, a.findUnusedFeatureEntities(
+				featuresByModule[moduleName],
+				providersByModule[moduleName],
+				absPath,
+			)...)
+		}
+
+		if a.options.EnableQueues {
+			result.Findings = append(result.Findings, a.findQueueIssues(
+				queuesByModule[moduleName],
+				locationsByModule[moduleName].Elements[ElementQueues],
+				importsByModule[moduleName],
+				providersByModule[moduleName],
+				absPath,
+			)...)
+		}
+
+		if a.options.EnableMissingImports {
+			result.Findings = append(result.Findings, a.findMissingImports(
+				moduleName,
+				referencesByModule[moduleName],
+				exportsByModule[moduleName],
+				providersByModule[moduleName],
+				absPath,
+			)...)
+		}
+
+		if result != nil && (result.HasFindings() || a.options.IncludeCleanModules) {
+			if locations, ok := locationsByModule[moduleName]; ok {
+				locateResult(result, locations)
+			}
+			if a.options.IncludeModuleMetadata {
+				result.Metadata = a.moduleMetadata(
+					importsByModule[moduleName],
+					providersByModule[moduleName],
+					exportsByModule[moduleName],
+					absPath,
+				)
+			}
 			results = append(results, result)
 		}
 	}
@@ -115,6 +208,32 @@ func (a *Analyzer) AnalyzeFile(filePath string) ([]*ModuleAnalysisResult, error)
 	return results, nil
 }
 
+// moduleMetadata lists a module's metadata, resolving its imports to the
+// files declaring them
+func (a *Analyzer) moduleMetadata(imports, providers, exports []string, filePath string) *ModuleMetadata {
+	metadata := &ModuleMetadata{
+		Imports:     append([]string{}, imports...),
+		Providers:   append([]string{}, providers...),
+		Exports:     append([]string{}, exports...),
+		ImportFiles: make(map[string]string),
+	}
+	importPaths, err := a.parser.GetImportPaths(filePath)
+	if err != nil {
+		return metadata
+	}
+	for _, importName := range imports {
+		declarationFile := a.resolveDeclarationFile(importName, importPaths, filePath)
+		if declarationFile == "" {
+			continue
+		}
+		if relative, err := filepath.Rel(a.options.WorkingDirectory, declarationFile); err == nil {
+			declarationFile = relative
+		}
+		metadata.ImportFiles[importName] = declarationFile
+	}
+	return metadata
+}
+
 // AnalyzeDirectory recursively analyzes all TypeScript files in a directory
 func (a *Analyzer) AnalyzeDirectory(dirPath string) ([]*ModuleAnalysisResult, error) {
 	files, err := filesystem.FindTypeScriptFiles(dirPath)
@@ -170,6 +289,7 @@ func (a *Analyzer) analyzeModuleImports(
 	imports []string,
 	exports []string,
 	providers []string,
+	injections []string,
 	relativePath string,
 	absolutePath string,
 ) *ModuleAnalysisResult {
@@ -221,48 +341,74 @@ func (a *Analyzer) analyzeModuleImports(
 	}
 
 	// Analyze actual usage of imports
-	result.UnusedImports = a.findUnusedImports(filteredImports, providers, absolutePath)
+	unused, used := a.findUnusedImports(filteredImports, providers, injections, absolutePath)
+	if unused != nil {
+		result.UnusedImports = unused
+	}
+	result.UsedImports = used
 
 	return result
 }
 
-// findUnusedImports determines which imports are actually unused by analyzing provider dependencies
-func (a *Analyzer) findUnusedImports(imports []string, providers []string, filePath string) []string {
-	if len(providers) == 0 {
+// findUnusedImports determines which imports are actually unused by analyzing provider dependencies.
+// injections are the tokens the module's provider definitions inject directly, e.g. useFactory inject arrays.
+// The used imports are returned with what they are used for.
+func (a *Analyzer) findUnusedImports(imports []string, providers []string, injections []string, filePath string) ([]string, []ImportUsage) {
+	if len(providers) == 0 && len(injections) == 0 {
 		// If there are no providers/controllers, all imports are potentially unused
 		// However, this is a conservative check - modules might still be used in other ways
-		return imports
+		return imports, nil
+	}
+
+	// Imports and providers are resolved through the module file's import statements
+	importPaths, err := a.parser.GetImportPaths(filePath)
+	if err != nil {
+		// Without import paths nothing can be resolved, conservatively assume all imports are used
+		return []string{}, unanalyzedImports(imports)
 	}
 
 	// Build the dependency map for concurrent analysis
 	importData := make([]moduleImportData, len(imports))
 	for i, importName := range imports {
+		path := a.resolveDeclarationFile(importName, importPaths, filePath)
 		importData[i] = moduleImportData{
-			name: importName,
-			path: a.pathResolver.ResolveImportPath(filepath.Dir(filePath), importName),
+			name:     importName,
+			path:     path,
+			external: path == "",
 		}
 	}
 
-	providerList := make([]providerData, len(providers))
-	for i, providerName := range providers {
-		providerList[i] = providerData{
-			name: providerName,
-			path: a.pathResolver.ResolveImportPath(filepath.Dir(filePath), providerName),
+	var providerList []providerData
+	for _, providerName := range providers {
+		path := a.resolveDeclarationFile(providerName, importPaths, filePath)
+		if path == "" {
+			// Providers from packages have their own dependencies
+			continue
 		}
+		providerList = append(providerList, providerData{
+			name: providerName,
+			path: path,
+		})
 	}
 
+	moduleInjections := append(append([]string{}, injections...), a.resolveTokenKeys(injections, filePath)...)
+
 	// Perform concurrent analysis
-	return a.analyzeImportUsage(importData, providerList)
+	return a.analyzeImportUsage(importData, providerList, moduleInjections)
 }
 
-// analyzeImportUsage performs the actual dependency analysis using concurrent processing
-func (a *Analyzer) analyzeImportUsage(imports []moduleImportData, providers []providerData) []string {
+// analyzeImportUsage performs the actual dependency analysis using concurrent processing.
+// It returns the unused imports and the usage of the others.
+func (a *Analyzer) analyzeImportUsage(imports []moduleImportData, providers []providerData, injections []string) ([]string, []ImportUsage) {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	errorChan := make(chan error, 1)
 
 	// Concurrently get exports for each imported module
 	for i := range imports {
+		if imports[i].external {
+			continue
+		}
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
@@ -308,23 +454,41 @@ func (a *Analyzer) analyzeImportUsage(imports []moduleImportData, providers []pr
 	// Check for errors
 	if err := <-errorChan; err != nil {
 		// On error, conservatively assume all imports are used
-		return []string{}
+		names := make([]string, len(imports))
+		for i, importModule := range imports {
+			names[i] = importModule.name
+		}
+		return []string{}, unanalyzedImports(names)
 	}
 
-	// Build a set of all imports used by providers
-	usedImports := make(map[string]bool)
+	// Map all imports and tokens used by providers to the first provider
+	// using them, or to no provider for the module's own injections
+	usedImports := make(map[string]string)
 	for _, provider := range providers {
 		for _, fileImport := range provider.fileImports {
-			usedImports[fileImport] = true
+			if _, ok := usedImports[fileImport]; !ok {
+				usedImports[fileImport] = provider.name
+			}
+		}
+	}
+	for _, injection := range injections {
+		if _, ok := usedImports[injection]; !ok {
+			usedImports[injection] = ""
 		}
 	}
 
 	// Check which imported module exports are actually used
 	var unusedImports []string
+	var usages []ImportUsage
 	for _, importModule := range imports {
+		if importModule.external {
+			usages = append(usages, ImportUsage{Name: importModule.name, External: true})
+			continue
+		}
 		found := false
 		for _, export := range importModule.exports {
-			if usedImports[export] {
+			if provider, ok := usedImports[export]; ok {
+				usages = append(usages, ImportUsage{Name: importModule.name, Provider: provider, Symbol: keyDisplayName(export)})
 				found = true
 				break
 			}
@@ -334,10 +498,199 @@ func (a *Analyzer) analyzeImportUsage(imports []moduleImportData, providers []pr
 		}
 	}
 
-	return unusedImports
+	return unusedImports, usages
+}
+
+// unanalyzedImports returns the usage of imports that count as used because
+// they could not be analyzed
+func unanalyzedImports(imports []string) []ImportUsage {
+	usages := make([]ImportUsage, len(imports))
+	for i, name := range imports {
+		usages[i] = ImportUsage{Name: name}
+	}
+	return usages
+}
+
+// findUnusedFeatureEntities reports ORM entities registered with forFeature()
+// whose repository or model no provider injects
+func (a *Analyzer) findUnusedFeatureEntities(entities []FeatureEntity, providers []string, filePath string) []Finding {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	var sourceCode []byte
+	if a.options.EnableIgnores {
+		sourceCode, _ = filesystem.ReadFile(filePath)
+	}
+
+	providerFiles, err := a.providerFiles(providers, filePath)
+	if err != nil {
+		return nil
+	}
+
+	injected := make(map[string]bool)
+	for _, providerFile := range providerFiles {
+		models, err := a.parser.GetInjectedModels(providerFile)
+		if err != nil {
+			// A provider that cannot be read might inject anything
+			return nil
+		}
+		for _, model := range models {
+			injected[model] = true
+		}
+	}
+
+	var findings []Finding
+	for _, entity := range entities {
+		if injected[entity.Name] {
+			continue
+		}
+		if sourceCode != nil && a.ignoreDetector.ShouldIgnoreImport(entity.Name, sourceCode) {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleUnusedFeatureEntity,
+			Name:    entity.Name,
+			Message: fmt.Sprintf("%s is registered with %s.forFeature() but no provider injects it", entity.Name, entity.Module),
+		})
+	}
+	return findings
+}
+
+// findQueueIssues reports queues registered with BullModule that no provider
+// injects or processes, and queues injected without being registered by the
+// module or the modules it imports
+func (a *Analyzer) findQueueIssues(
+	registrations []string,
+	registrationLocations map[string]Location,
+	imports []string,
+	providers []string,
+	filePath string,
+) []Finding {
+	var sourceCode []byte
+	if a.options.EnableIgnores {
+		sourceCode, _ = filesystem.ReadFile(filePath)
+	}
+
+	providerFiles, err := a.providerFiles(providers, filePath)
+	if err != nil {
+		return nil
+	}
+
+	used := make(map[string]bool)
+	var injected []string
+	injectedNames := make(map[string]string)
+	for _, providerFile := range providerFiles {
+		usages, err := a.parser.GetQueueUsages(providerFile)
+		if err != nil {
+			// A provider that cannot be read might use any queue
+			return nil
+		}
+		for _, usage := range usages {
+			key := a.resolveTokenKey(usage.Name, providerFile)
+			used[key] = true
+			if usage.Decorator == QueueInjectDecorator {
+				if _, seen := injectedNames[key]; !seen {
+					injected = append(injected, key)
+					injectedNames[key] = usage.Name
+				}
+			}
+		}
+	}
+
+	registered := make(map[string]bool)
+	var findings []Finding
+	for _, registration := range registrations {
+		key := a.resolveTokenKey(registration, filePath)
+		registered[key] = true
+		if used[key] {
+			continue
+		}
+		name := TokenDisplayName(registration)
+		if sourceCode != nil && (a.ignoreDetector.ShouldIgnoreImport(name, sourceCode) ||
+			a.ignoresLocation(registrationLocations[name], sourceCode)) {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleUnusedQueue,
+			Name:    name,
+			Message: fmt.Sprintf("Queue %s is registered but no provider injects or processes it", name),
+		})
+	}
+
+	if len(injected) == 0 {
+		return findings
+	}
+
+	// Queues registered by imported modules are available to the providers
+	importPaths, err := a.parser.GetImportPaths(filePath)
+	if err != nil {
+		return findings
+	}
+	for _, importName := range imports {
+		moduleFile := a.resolveDeclarationFile(importName, importPaths, filePath)
+		if moduleFile == "" || moduleFile == filePath {
+			continue
+		}
+		queuesByModule, err := a.parser.GetQueueRegistrationsByModule(moduleFile)
+		if err != nil {
+			// The imported module might register any queue
+			return findings
+		}
+		for _, queue := range queuesByModule[importName] {
+			registered[a.resolveTokenKey(queue, moduleFile)] = true
+		}
+	}
+
+	for _, key := range injected {
+		if registered[key] {
+			continue
+		}
+		name := TokenDisplayName(injectedNames[key])
+		if sourceCode != nil && a.ignoreDetector.ShouldIgnoreImport(name, sourceCode) {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleUnregisteredQueue,
+			Name:    name,
+			Message: fmt.Sprintf("Queue %s is injected but not registered with BullModule.registerQueue()", name),
+		})
+	}
+	return findings
+}
+
+// ignoresLocation reports whether a line spanned by the location has a
+// disable-line comment
+func (a *Analyzer) ignoresLocation(location Location, sourceCode []byte) bool {
+	for line := max(location.StartLine, 1); line <= location.EndLine; line++ {
+		if a.ignoreDetector.ShouldIgnoreLine(line, sourceCode) {
+			return true
+		}
+	}
+	return false
 }
 
-// getModuleExports gets the exports from a module file
+// providerFiles resolves the files declaring the module's providers. The
+// module file itself is included since it can declare providers inline, e.g.
+// factory providers.
+func (a *Analyzer) providerFiles(providers []string, filePath string) ([]string, error) {
+	importPaths, err := a.parser.GetImportPaths(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{filePath}
+	for _, providerName := range providers {
+		if path := a.resolveDeclarationFile(providerName, importPaths, filePath); path != "" && path != filePath {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// getModuleExports gets the exports from a module file, with exported const
+// tokens replaced by their token keys, and the queues the module registers
+// when it exports BullModule
 func (a *Analyzer) getModuleExports(moduleName, filePath string) ([]string, error) {
 	result, err := a.parser.GetExportsByModule(filePath)
 	if err != nil {
@@ -345,15 +698,26 @@ func (a *Analyzer) getModuleExports(moduleName, filePath string) ([]string, erro
 	}
 
 	// Return exports for this specific module
-	if exports, exists := result[moduleName]; exists {
-		return exports, nil
+	exports, exists := result[moduleName]
+	if !exists {
+		// If no exports found for this module, it might not export anything
+		return []string{}, nil
 	}
+	exports = a.resolveTokenKeys(exports, filePath)
 
-	// If no exports found for this module, it might not export anything
-	return []string{}, nil
+	if slices.Contains(exports, BullModuleName) {
+		queuesByModule, err := a.parser.GetQueueRegistrationsByModule(filePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, queue := range queuesByModule[moduleName] {
+			exports = append(exports, queueKey(a.resolveTokenKey(queue, filePath)))
+		}
+	}
+	return exports, nil
 }
 
-// getProviderFileImports gets the file imports for a provider/controller file
+// getProviderFileImports gets the file imports and injected tokens for a provider/controller file
 func (a *Analyzer) getProviderFileImports(filePath string) ([]string, error) {
 	importPaths, err := a.parser.GetImportPaths(filePath)
 	if err != nil {
@@ -369,5 +733,126 @@ func (a *Analyzer) getProviderFileImports(filePath string) ([]string, error) {
 		}
 	}
 
+	// Tokens injected with @Inject() count as usage, compared by value for string tokens
+	injectedTokens, err := a.parser.GetInjectedTokens(filePath)
+	if err != nil {
+		return nil, err
+	}
+	importNames = append(importNames, injectedTokens...)
+	importNames = append(importNames, a.resolveTokenKeys(importNames, filePath)...)
+
+	// Injected and processed queues use the modules registering them
+	queueUsages, err := a.parser.GetQueueUsages(filePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, usage := range queueUsages {
+		importNames = append(importNames, queueKey(a.resolveTokenKey(usage.Name, filePath)))
+	}
+
 	return importNames, nil
 }
+
+// maxReExportDepth limits how many barrel files are followed to find the
+// declaration of a token
+const maxReExportDepth = 3
+
+// resolveTokenKeys replaces identifier tokens that refer to const
+// declarations with their token keys: the string token key for consts with a
+// string value, and a key naming the declaring file for others, e.g. Symbol()
+// tokens. Consts are looked up in filePath itself or in the file they are
+// imported from; other tokens are returned unchanged.
+func (a *Analyzer) resolveTokenKeys(tokens []string, filePath string) []string {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	importPaths, err := a.parser.GetImportPaths(filePath)
+	if err != nil {
+		return tokens
+	}
+
+	keys := make([]string, len(tokens))
+	for i, token := range tokens {
+		keys[i] = token
+		if IsStringTokenKey(token) {
+			continue
+		}
+		declarationFile, depth := filePath, 0
+		if importPath, ok := importPaths[token]; ok {
+			declarationFile, depth = a.resolveSourceFile(filepath.Dir(filePath), importPath), maxReExportDepth
+			if declarationFile == "" {
+				continue
+			}
+		}
+		if key, ok := a.findTokenDeclaration(token, declarationFile, depth); ok {
+			keys[i] = key
+		}
+	}
+	return keys
+}
+
+// findTokenDeclaration returns the token key of a const declared in
+// filePath, or in the files it re-exports the const from, up to depth
+// re-exports away
+func (a *Analyzer) findTokenDeclaration(token, filePath string, depth int) (string, bool) {
+	declarations, err := a.parser.GetTokenDeclarations(filePath)
+	if err != nil {
+		return "", false
+	}
+	if key, ok := declarations[token]; ok {
+		if key == "" {
+			key = declaredTokenKey(filePath, token)
+		}
+		return key, true
+	}
+	if depth == 0 {
+		return "", false
+	}
+
+	reExports, err := a.parser.GetReExports(filePath)
+	if err != nil {
+		return "", false
+	}
+	for _, path := range slices.Concat(reExports[token], reExports["*"]) {
+		if file := a.resolveSourceFile(filepath.Dir(filePath), path); file != "" {
+			if key, ok := a.findTokenDeclaration(token, file, depth-1); ok {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// resolveTokenKey resolves an identifier token to the token key of the const
+// it refers to. Tokens that cannot be resolved are returned unchanged.
+func (a *Analyzer) resolveTokenKey(token string, filePath string) string {
+	return a.resolveTokenKeys([]string{token}, filePath)[0]
+}
+
+// resolveDeclarationFile finds the file that declares name, following the
+// import statements of filePath. Names that are not imported are assumed to
+// be declared in filePath itself. An empty string is returned for names
+// imported from outside the project.
+func (a *Analyzer) resolveDeclarationFile(name string, importPaths map[string]string, filePath string) string {
+	importPath, ok := importPaths[name]
+	if !ok {
+		return filePath
+	}
+	return a.resolveSourceFile(filepath.Dir(filePath), importPath)
+}
+
+// resolveSourceFile resolves an import path to a TypeScript file, falling
+// back to the index file of a directory. An empty string is returned when no
+// such file exists, e.g. for package imports.
+func (a *Analyzer) resolveSourceFile(baseDir, importPath string) string {
+	resolved := a.pathResolver.ResolveImportPath(baseDir, importPath)
+	if filesystem.FileExists(resolved) {
+		return resolved
+	}
+	index := filepath.Join(strings.TrimSuffix(resolved, ".ts"), "index.ts")
+	if filesystem.FileExists(index) {
+		return index
+	}
+	return ""
+}
@@ -3,6 +3,8 @@ package analysis_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
@@ -11,10 +13,21 @@ import (
 // Mock implementations for testing
 
 type mockModuleParser struct {
-	modules   map[string]*analysis.ModuleInfo
-	imports   map[string]map[string][]string
-	exports   map[string]map[string][]string
-	providers map[string]map[string][]string
+	modules        map[string]*analysis.ModuleInfo
+	imports        map[string]map[string][]string
+	exports        map[string]map[string][]string
+	providers      map[string]map[string][]string
+	injectedTokens map[string][]string
+	tokens         map[string]map[string]string
+	reExports      map[string]map[string][]string
+	features       map[string]map[string][]analysis.FeatureEntity
+	injectedModels map[string][]string
+	queues         map[string]map[string][]string
+	queueUsages    map[string][]analysis.QueueUsage
+	globalModules  map[string][]string
+	dependencies   map[string][]string
+	importPaths    map[string]map[string]string
+	locations      map[string]map[string]analysis.ModuleLocations
 }
 
 func (m *mockModuleParser) ParseModuleInfo(filePath string) (*analysis.ModuleInfo, error) {
@@ -46,10 +59,63 @@ func (m *mockModuleParser) GetProvidersByModule(filePath string) (map[string][]s
 }
 
 func (m *mockModuleParser) GetImportPaths(filePath string) (map[string]string, error) {
-	// Simple mock implementation - return empty map
+	if importPaths, ok := m.importPaths[filePath]; ok {
+		return importPaths, nil
+	}
+	return map[string]string{}, nil
+}
+
+func (m *mockModuleParser) GetProviderInjectionsByModule(filePath string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (m *mockModuleParser) GetInjectedTokens(filePath string) ([]string, error) {
+	return m.injectedTokens[filePath], nil
+}
+
+func (m *mockModuleParser) GetTokenDeclarations(filePath string) (map[string]string, error) {
+	if tokens, ok := m.tokens[filePath]; ok {
+		return tokens, nil
+	}
 	return map[string]string{}, nil
 }
 
+func (m *mockModuleParser) GetReExports(filePath string) (map[string][]string, error) {
+	return m.reExports[filePath], nil
+}
+
+func (m *mockModuleParser) GetFeatureEntitiesByModule(filePath string) (map[string][]analysis.FeatureEntity, error) {
+	return m.features[filePath], nil
+}
+
+func (m *mockModuleParser) GetInjectedModels(filePath string) ([]string, error) {
+	return m.injectedModels[filePath], nil
+}
+
+func (m *mockModuleParser) GetQueueRegistrationsByModule(filePath string) (map[string][]string, error) {
+	return m.queues[filePath], nil
+}
+
+func (m *mockModuleParser) GetQueueUsages(filePath string) ([]analysis.QueueUsage, error) {
+	return m.queueUsages[filePath], nil
+}
+
+func (m *mockModuleParser) GetImportReferencesByModule(filePath string) (map[string][]string, error) {
+	return m.GetImportsByModule(filePath)
+}
+
+func (m *mockModuleParser) GetGlobalModules(filePath string) ([]string, error) {
+	return m.globalModules[filePath], nil
+}
+
+func (m *mockModuleParser) GetConstructorDependencies(filePath string) ([]string, error) {
+	return m.dependencies[filePath], nil
+}
+
+func (m *mockModuleParser) GetModuleLocations(filePath string) (map[string]analysis.ModuleLocations, error) {
+	return m.locations[filePath], nil
+}
+
 type mockPathResolver struct{}
 
 func (m *mockPathResolver) ResolveImportPath(baseDir, importPath string) string {
@@ -68,6 +134,11 @@ func (m *mockIgnoreDetector) ShouldIgnoreImport(moduleName string, source []byte
 	return moduleName == "IgnoredModule"
 }
 
+func (m *mockIgnoreDetector) ShouldIgnoreLine(line int, source []byte) bool {
+	lines := strings.Split(string(source), "\n")
+	return line <= len(lines) && strings.Contains(lines[line-1], "// ignore-line")
+}
+
 type mockReExportDetector struct{}
 
 func (m *mockReExportDetector) GetReExportedModules(imports []string, exports []string) []string {
@@ -212,3 +283,588 @@ func TestAnalyzer_AnalyzeDirectory_NoTypeScriptFiles(t *testing.T) {
 		t.Error("Expected error for directory with no TypeScript files")
 	}
 }
+
+func TestAnalyzer_AnalyzeFile_StringTokens(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+
+	// MailerModule exports a string token that the provider injects with
+	// @Inject('MAILER'); OtherModule's export is never used
+	parser := &mockModuleParser{
+		imports: map[string]map[string][]string{
+			testFile: {"TestModule": {"MailerModule", "OtherModule"}},
+		},
+		exports: map[string]map[string][]string{
+			testFile: {
+				"MailerModule": {analysis.StringTokenKey("MAILER")},
+				"OtherModule":  {"OtherService"},
+			},
+		},
+		providers: map[string]map[string][]string{
+			testFile: {"TestModule": {"Provider1"}},
+		},
+		injectedTokens: map[string][]string{
+			testFile: {analysis.StringTokenKey("MAILER")},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir},
+	)
+
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := analyzer.AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if len(results[0].UnusedImports) != 1 || results[0].UnusedImports[0] != "OtherModule" {
+		t.Errorf("Expected only OtherModule to be unused, got %v", results[0].UnusedImports)
+	}
+	expectedUsage := []analysis.ImportUsage{{Name: "MailerModule", Provider: "Provider1", Symbol: analysis.StringTokenKey("MAILER")}}
+	if !reflect.DeepEqual(results[0].UsedImports, expectedUsage) {
+		t.Errorf("Expected MailerModule to be used by Provider1, got %+v", results[0].UsedImports)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_DeclaredTokens(t *testing.T) {
+	tempDir := t.TempDir()
+	appFile := filepath.Join(tempDir, "app.module.ts")
+	mailerFile := filepath.Join(tempDir, "mailer.module.ts")
+	mailerTokensFile := filepath.Join(tempDir, "mailer.tokens.ts")
+	eventsFile := filepath.Join(tempDir, "events.module.ts")
+	eventsTokensFile := filepath.Join(tempDir, "events.tokens.ts")
+	indexFile := filepath.Join(tempDir, "index.ts")
+	serviceFile := filepath.Join(tempDir, "notifier.service.ts")
+	for _, file := range []string{appFile, mailerFile, mailerTokensFile, eventsFile, eventsTokensFile, indexFile, serviceFile} {
+		if err := os.WriteFile(file, []byte("test content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// MailerModule and EventsModule each export a Symbol() token named
+	// EVENTS, declared in different files; NotifierService injects the one
+	// of EventsModule through an index file re-exporting it
+	parser := &mockModuleParser{
+		imports: map[string]map[string][]string{
+			appFile: {"AppModule": {"MailerModule", "EventsModule"}},
+		},
+		exports: map[string]map[string][]string{
+			mailerFile: {"MailerModule": {"EVENTS"}},
+			eventsFile: {"EventsModule": {"EVENTS"}},
+		},
+		providers: map[string]map[string][]string{
+			appFile: {"AppModule": {"NotifierService"}},
+		},
+		importPaths: map[string]map[string]string{
+			appFile:     {"MailerModule": "mailer.module.ts", "EventsModule": "events.module.ts", "NotifierService": "notifier.service.ts"},
+			mailerFile:  {"EVENTS": "mailer.tokens.ts"},
+			eventsFile:  {"EVENTS": "events.tokens.ts"},
+			serviceFile: {"EVENTS": "index.ts"},
+		},
+		tokens: map[string]map[string]string{
+			mailerTokensFile: {"EVENTS": ""},
+			eventsTokensFile: {"EVENTS": ""},
+		},
+		reExports: map[string]map[string][]string{
+			indexFile: {"*": {"events.tokens.ts"}},
+		},
+		injectedTokens: map[string][]string{
+			serviceFile: {"EVENTS"},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir},
+	)
+
+	results, err := analyzer.AnalyzeFile(appFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if !reflect.DeepEqual(results[0].UnusedImports, []string{"MailerModule"}) {
+		t.Errorf("Expected only MailerModule to be unused, got %v", results[0].UnusedImports)
+	}
+	expectedUsage := []analysis.ImportUsage{{Name: "EventsModule", Provider: "NotifierService", Symbol: "EVENTS"}}
+	if !reflect.DeepEqual(results[0].UsedImports, expectedUsage) {
+		t.Errorf("Expected EventsModule to be used by NotifierService, got %+v", results[0].UsedImports)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_FeatureEntities(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+
+	// The module has no imports to analyze, only forFeature() entities, and
+	// its provider injects the User repository but not the Order one
+	parser := &mockModuleParser{
+		providers: map[string]map[string][]string{
+			testFile: {"UsersModule": {"UsersService"}},
+		},
+		features: map[string]map[string][]analysis.FeatureEntity{
+			testFile: {"UsersModule": {
+				{Module: "TypeOrmModule", Name: "User"},
+				{Module: "TypeOrmModule", Name: "Order"},
+			}},
+		},
+		injectedModels: map[string][]string{
+			testFile: {"User"},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableFeatureEntities: true},
+	)
+
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := analyzer.AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	findings := results[0].FindingsByRule(analysis.RuleUnusedFeatureEntity)
+	if len(findings) != 1 || findings[0].Name != "Order" {
+		t.Errorf("Expected only Order to be flagged, got %v", findings)
+	}
+	if len(results[0].UnusedImports) != 0 {
+		t.Errorf("Expected no unused imports, got %v", results[0].UnusedImports)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_Queues(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+
+	// 'emails' is injected, 'reports' is registered but unused and 'audio'
+	// is injected without being registered
+	parser := &mockModuleParser{
+		providers: map[string]map[string][]string{
+			testFile: {"JobsModule": {"JobsService"}},
+		},
+		queues: map[string]map[string][]string{
+			testFile: {"JobsModule": {analysis.StringTokenKey("emails"), analysis.StringTokenKey("reports")}},
+		},
+		queueUsages: map[string][]analysis.QueueUsage{
+			testFile: {
+				{Name: analysis.StringTokenKey("emails"), Decorator: analysis.QueueInjectDecorator},
+				{Name: analysis.StringTokenKey("audio"), Decorator: analysis.QueueInjectDecorator},
+			},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableQueues: true},
+	)
+
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := analyzer.AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	unused := results[0].FindingsByRule(analysis.RuleUnusedQueue)
+	if len(unused) != 1 || unused[0].Name != "reports" {
+		t.Errorf("Expected only reports to be unused, got %v", unused)
+	}
+	unregistered := results[0].FindingsByRule(analysis.RuleUnregisteredQueue)
+	if len(unregistered) != 1 || unregistered[0].Name != "audio" {
+		t.Errorf("Expected only audio to be unregistered, got %v", unregistered)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_IgnoredQueueRegistration(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+
+	// Neither queue is used; the registration of 'reports' ends on a line
+	// with an ignore comment
+	sourceCode := "@Module({\n" +
+		"  imports: [\n" +
+		"    BullModule.registerQueue({ name: 'emails' }),\n" +
+		"    BullModule.registerQueue({\n" +
+		"      name: 'reports',\n" +
+		"    }), // ignore-line\n" +
+		"  ],\n" +
+		"})\n"
+	parser := &mockModuleParser{
+		providers: map[string]map[string][]string{
+			testFile: {"JobsModule": {"JobsService"}},
+		},
+		queues: map[string]map[string][]string{
+			testFile: {"JobsModule": {analysis.StringTokenKey("emails"), analysis.StringTokenKey("reports")}},
+		},
+		locations: map[string]map[string]analysis.ModuleLocations{
+			testFile: {"JobsModule": {
+				Elements: map[string]map[string]analysis.Location{
+					analysis.ElementQueues: {
+						"emails":  {StartLine: 3, EndLine: 3},
+						"reports": {StartLine: 4, EndLine: 6},
+					},
+				},
+			}},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableQueues: true, EnableIgnores: true},
+	)
+
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := analyzer.AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	unused := results[0].FindingsByRule(analysis.RuleUnusedQueue)
+	if len(unused) != 1 || unused[0].Name != "emails" {
+		t.Errorf("Expected only emails to be unused, got %v", unused)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_MissingImports(t *testing.T) {
+	tempDir := t.TempDir()
+	ordersFile := filepath.Join(tempDir, "orders.module.ts")
+	usersFile := filepath.Join(tempDir, "users.module.ts")
+	configFile := filepath.Join(tempDir, "config.module.ts")
+	logAFile := filepath.Join(tempDir, "log-a.module.ts")
+	logBFile := filepath.Join(tempDir, "log-b.module.ts")
+
+	// UsersService is exported by a single module, ConfigService by a global
+	// module and Logger by two modules
+	parser := &mockModuleParser{
+		providers: map[string]map[string][]string{
+			ordersFile: {"OrdersModule": {"OrdersService"}},
+		},
+		exports: map[string]map[string][]string{
+			usersFile:  {"UsersModule": {"UsersService"}},
+			configFile: {"ConfigModule": {"ConfigService"}},
+			logAFile:   {"LogAModule": {"Logger"}},
+			logBFile:   {"LogBModule": {"Logger"}},
+		},
+		globalModules: map[string][]string{
+			configFile: {"ConfigModule"},
+		},
+		dependencies: map[string][]string{
+			ordersFile: {"UsersService", "ConfigService", "Logger"},
+		},
+		importPaths: map[string]map[string]string{
+			ordersFile: {
+				"UsersService":  "users.service.ts",
+				"ConfigService": "config.service.ts",
+				"Logger":        "logger.ts",
+			},
+			usersFile:  {"UsersService": "users.service.ts"},
+			configFile: {"ConfigService": "config.service.ts"},
+			logAFile:   {"Logger": "logger.ts"},
+			logBFile:   {"Logger": "logger.ts"},
+		},
+	}
+
+	for _, name := range []string{"orders.module.ts", "users.module.ts", "config.module.ts", "log-a.module.ts", "log-b.module.ts", "users.service.ts", "config.service.ts", "logger.ts"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("test content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableMissingImports: true},
+	)
+
+	results, err := analyzer.AnalyzeFile(ordersFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	findings := results[0].FindingsByRule(analysis.RuleMissingImport)
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 missing imports, got %v", findings)
+	}
+	if findings[0].Name != "UsersService" || len(findings[0].Candidates) != 1 ||
+		findings[0].Candidates[0] != (analysis.ModuleCandidate{Name: "UsersModule", FilePath: "users.module.ts"}) {
+		t.Errorf("Expected UsersService to be exported by UsersModule, got %v", findings[0])
+	}
+	if findings[1].Name != "Logger" || len(findings[1].Candidates) != 2 {
+		t.Errorf("Expected Logger to have two candidate modules, got %v", findings[1])
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_Locations(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+
+	declaration := analysis.Location{StartLine: 9, StartColumn: 14, EndLine: 9, EndColumn: 25, StartByte: 180, EndByte: 191}
+	unusedModule := analysis.Location{StartLine: 5, StartColumn: 5, EndLine: 5, EndColumn: 17, StartByte: 90, EndByte: 102}
+	order := analysis.Location{StartLine: 6, StartColumn: 30, EndLine: 6, EndColumn: 35, StartByte: 130, EndByte: 135}
+	parser := &mockModuleParser{
+		imports: map[string]map[string][]string{
+			testFile: {"UsersModule": {"UnusedModule"}},
+		},
+		features: map[string]map[string][]analysis.FeatureEntity{
+			testFile: {"UsersModule": {{Module: "TypeOrmModule", Name: "Order"}}},
+		},
+		locations: map[string]map[string]analysis.ModuleLocations{
+			testFile: {"UsersModule": {
+				Declaration: declaration,
+				Elements: map[string]map[string]analysis.Location{
+					"imports":                {"UnusedModule": unusedModule},
+					analysis.ElementEntities: {"Order": order},
+				},
+			}},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableFeatureEntities: true},
+	)
+
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := analyzer.AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	result := results[0]
+	if result.Location == nil || *result.Location != declaration {
+		t.Errorf("Expected the module location %v, got %v", declaration, result.Location)
+	}
+	if location, ok := result.ImportLocations["UnusedModule"]; !ok || location != unusedModule {
+		t.Errorf("Expected the UnusedModule location %v, got %v", unusedModule, result.ImportLocations)
+	}
+	findings := result.FindingsByRule(analysis.RuleUnusedFeatureEntity)
+	if len(findings) != 1 || findings[0].Location == nil || *findings[0].Location != order {
+		t.Errorf("Expected the Order finding at %v, got %v", order, findings)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_IncludeCleanModules(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// The module's provider injects nothing, so nothing is missing
+	parser := &mockModuleParser{
+		providers: map[string]map[string][]string{
+			testFile: {"AppModule": {"AppService"}},
+		},
+	}
+
+	for _, includeClean := range []bool{false, true} {
+		analyzer := analysis.NewAnalyzer(
+			parser,
+			&mockPathResolver{},
+			&mockIgnoreDetector{},
+			&mockReExportDetector{},
+			analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableMissingImports: true, IncludeCleanModules: includeClean},
+		)
+
+		results, err := analyzer.AnalyzeFile(testFile)
+		if err != nil {
+			t.Fatalf("AnalyzeFile failed: %v", err)
+		}
+
+		expected := 0
+		if includeClean {
+			expected = 1
+		}
+		if len(results) != expected {
+			t.Errorf("IncludeCleanModules=%v: expected %d results, got %d", includeClean, expected, len(results))
+		}
+		if len(results) == 1 && results[0].HasFindings() {
+			t.Errorf("Expected a clean module, got %+v", results[0])
+		}
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_IncludeModuleMetadata(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "app.module.ts")
+	usersFile := filepath.Join(tempDir, "users", "users.module.ts")
+	if err := os.MkdirAll(filepath.Dir(usersFile), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, file := range []string{testFile, usersFile} {
+		if err := os.WriteFile(file, []byte("test content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	parser := &mockModuleParser{
+		imports: map[string]map[string][]string{
+			testFile: {"AppModule": {"UsersModule", "ConfigModule"}},
+		},
+		exports: map[string]map[string][]string{
+			testFile: {"AppModule": {"AppService"}},
+		},
+		providers: map[string]map[string][]string{
+			testFile: {"AppModule": {"AppService"}},
+		},
+		importPaths: map[string]map[string]string{
+			testFile: {"UsersModule": "users/users.module.ts", "ConfigModule": "@nestjs/config"},
+		},
+	}
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, IncludeCleanModules: true, IncludeModuleMetadata: true},
+	)
+
+	results, err := analyzer.AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Metadata == nil {
+		t.Fatalf("Expected one result with metadata, got %+v", results)
+	}
+
+	metadata := results[0].Metadata
+	if !reflect.DeepEqual(metadata.Imports, []string{"UsersModule", "ConfigModule"}) ||
+		!reflect.DeepEqual(metadata.Providers, []string{"AppService"}) ||
+		!reflect.DeepEqual(metadata.Exports, []string{"AppService"}) {
+		t.Errorf("Unexpected metadata %+v", metadata)
+	}
+	// Package imports have no file
+	expectedFiles := map[string]string{"UsersModule": filepath.Join("users", "users.module.ts")}
+	if !reflect.DeepEqual(metadata.ImportFiles, expectedFiles) {
+		t.Errorf("Expected import files %v, got %v", expectedFiles, metadata.ImportFiles)
+	}
+	// Package imports are not analyzed and count as used
+	if usage := results[0].UsedImports; len(usage) != 1 || usage[0] != (analysis.ImportUsage{Name: "ConfigModule", External: true}) {
+		t.Errorf("Expected ConfigModule to count as used, got %+v", usage)
+	}
+}
+
+func TestAnalyzer_AnalyzeFile_ReExportedQueue(t *testing.T) {
+	tempDir := t.TempDir()
+	mailFile := filepath.Join(tempDir, "mail.module.ts")
+	queuesFile := filepath.Join(tempDir, "queues.module.ts")
+	serviceFile := filepath.Join(tempDir, "mail.service.ts")
+	for _, file := range []string{mailFile, queuesFile, serviceFile} {
+		if err := os.WriteFile(file, []byte("test content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// QueuesModule registers the mail queue and exports BullModule, making
+	// the queue available to MailModule, whose MailService injects it
+	parser := &mockModuleParser{
+		imports: map[string]map[string][]string{
+			mailFile: {"MailModule": {"QueuesModule"}},
+		},
+		exports: map[string]map[string][]string{
+			queuesFile: {"QueuesModule": {analysis.BullModuleName}},
+		},
+		providers: map[string]map[string][]string{
+			mailFile: {"MailModule": {"MailService"}},
+		},
+		importPaths: map[string]map[string]string{
+			mailFile: {"QueuesModule": "queues.module.ts", "MailService": "mail.service.ts"},
+		},
+		queues: map[string]map[string][]string{
+			queuesFile: {"QueuesModule": {analysis.StringTokenKey("mail")}},
+		},
+		queueUsages: map[string][]analysis.QueueUsage{
+			serviceFile: {{Name: analysis.StringTokenKey("mail"), Decorator: analysis.QueueInjectDecorator}},
+		},
+	}
+
+	analyzer := analysis.NewAnalyzer(
+		parser,
+		&mockPathResolver{},
+		&mockIgnoreDetector{},
+		&mockReExportDetector{},
+		analysis.AnalysisOptions{WorkingDirectory: tempDir, EnableQueues: true, IncludeCleanModules: true},
+	)
+
+	results, err := analyzer.AnalyzeFile(mailFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].HasFindings() {
+		t.Errorf("Expected QueuesModule to be used for the mail queue, got %v and %v", results[0].UnusedImports, results[0].Findings)
+	}
+	expectedUsage := []analysis.ImportUsage{{Name: "QueuesModule", Provider: "MailService", Symbol: "queue 'mail'"}}
+	if !reflect.DeepEqual(results[0].UsedImports, expectedUsage) {
+		t.Errorf("Expected QueuesModule to be used by MailService, got %+v", results[0].UsedImports)
+	}
+
+	// Without exporting BullModule, the queue stays private to QueuesModule
+	parser.exports[queuesFile]["QueuesModule"] = []string{}
+	results, err = analyzer.AnalyzeFile(mailFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].UnusedImports) != 1 {
+		t.Errorf("Expected QueuesModule to be unused when it does not export BullModule, got %+v", results)
+	}
+}
@@ -18,6 +18,9 @@ type PathResolver interface {
 type IgnoreDetector interface {
 	ShouldIgnoreFile(source []byte) bool
 	ShouldIgnoreImport(moduleName string, source []byte) bool
+	// ShouldIgnoreLine reports whether a line (1-based) has a disable-line
+	// comment
+	ShouldIgnoreLine(line int, source []byte) bool
 }
 
 // ReExportDetector defines the interface for detecting re-exports
@@ -32,4 +35,16 @@ type ModuleParser interface {
 	GetExportsByModule(filePath string) (map[string][]string, error)
 	GetProvidersByModule(filePath string) (map[string][]string, error)
 	GetImportPaths(filePath string) (map[string]string, error)
+	GetProviderInjectionsByModule(filePath string) (map[string][]string, error)
+	GetInjectedTokens(filePath string) ([]string, error)
+	GetTokenDeclarations(filePath string) (map[string]string, error)
+	GetReExports(filePath string) (map[string][]string, error)
+	GetFeatureEntitiesByModule(filePath string) (map[string][]FeatureEntity, error)
+	GetInjectedModels(filePath string) ([]string, error)
+	GetQueueRegistrationsByModule(filePath string) (map[string][]string, error)
+	GetQueueUsages(filePath string) ([]QueueUsage, error)
+	GetImportReferencesByModule(filePath string) (map[string][]string, error)
+	GetGlobalModules(filePath string) ([]string, error)
+	GetConstructorDependencies(filePath string) ([]string, error)
+	GetModuleLocations(filePath string) (map[string]ModuleLocations, error)
 }
@@ -0,0 +1,42 @@
+package analysis
+
+// findingElements maps the rules whose findings name a metadata element to
+// the kind of that element
+var findingElements = map[string]string{
+	RuleUnusedFeatureEntity: ElementEntities,
+	RuleUnusedQueue:         ElementQueues,
+}
+
+// locateResult sets the locations of a module's imports and findings.
+// Findings without an element of their own point at the module class name.
+func locateResult(result *ModuleAnalysisResult, locations ModuleLocations) {
+	declaration := locations.Declaration
+	result.Location = &declaration
+
+	used := make([]string, len(result.UsedImports))
+	for i, usage := range result.UsedImports {
+		used[i] = usage.Name
+	}
+	for _, names := range [][]string{used, result.UnusedImports, result.IgnoredImports, result.ReExportedImports} {
+		for _, name := range names {
+			location, ok := locations.Element("imports", name)
+			if !ok {
+				continue
+			}
+			if result.ImportLocations == nil {
+				result.ImportLocations = make(map[string]Location)
+			}
+			result.ImportLocations[name] = location
+		}
+	}
+
+	for i := range result.Findings {
+		location := declaration
+		if key, ok := findingElements[result.Findings[i].RuleID]; ok {
+			if element, ok := locations.Element(key, result.Findings[i].Name); ok {
+				location = element
+			}
+		}
+		result.Findings[i].Location = &location
+	}
+}
@@ -0,0 +1,214 @@
+package analysis
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+)
+
+// exportedProvider is a provider exported by a module of the project
+type exportedProvider struct {
+	module ModuleCandidate
+	// declarationFile is the file declaring the provider, used to tell apart
+	// providers that share a name
+	declarationFile string
+	global          bool
+}
+
+// findMissingImports reports providers injected by the module's providers
+// that neither the module provides nor its imported modules export, but
+// another module of the project exports
+func (a *Analyzer) findMissingImports(
+	moduleName string,
+	imports []string,
+	exports []string,
+	providers []string,
+	filePath string,
+) []Finding {
+	var sourceCode []byte
+	if a.options.EnableIgnores {
+		sourceCode, _ = filesystem.ReadFile(filePath)
+	}
+
+	importPaths, err := a.parser.GetImportPaths(filePath)
+	if err != nil {
+		return nil
+	}
+
+	available := make(map[string]bool)
+	for _, name := range append(append([]string{}, providers...), exports...) {
+		available[name] = true
+	}
+	imported := make(map[string]bool)
+	for _, importName := range imports {
+		imported[importName] = true
+		moduleFile := a.resolveDeclarationFile(importName, importPaths, filePath)
+		if moduleFile == "" {
+			continue
+		}
+		moduleExports, err := a.getModuleExports(importName, moduleFile)
+		if err != nil {
+			continue
+		}
+		for _, name := range moduleExports {
+			available[name] = true
+		}
+	}
+
+	providerFiles, err := a.providerFiles(providers, filePath)
+	if err != nil {
+		return nil
+	}
+
+	var findings []Finding
+	reported := make(map[string]bool)
+	for _, providerFile := range providerFiles {
+		dependencies, err := a.parser.GetConstructorDependencies(providerFile)
+		if err != nil {
+			continue
+		}
+		providerImports, err := a.parser.GetImportPaths(providerFile)
+		if err != nil {
+			continue
+		}
+		for _, dependency := range dependencies {
+			if available[dependency] || reported[dependency] {
+				continue
+			}
+			declarationFile := a.resolveDeclarationFile(dependency, providerImports, providerFile)
+			if declarationFile == "" {
+				// Providers from packages come with their own modules
+				continue
+			}
+			candidates, satisfied := a.moduleCandidates(dependency, declarationFile, moduleName, filePath, imported)
+			if satisfied || len(candidates) == 0 {
+				continue
+			}
+			if sourceCode != nil && a.ignoreDetector.ShouldIgnoreImport(dependency, sourceCode) {
+				continue
+			}
+			reported[dependency] = true
+			findings = append(findings, missingImportFinding(dependency, candidates))
+		}
+	}
+	return findings
+}
+
+// moduleCandidates returns the project modules exporting the provider
+// declared in declarationFile. satisfied is set when one of them is global or
+// already imported, so no import is missing.
+func (a *Analyzer) moduleCandidates(
+	name string,
+	declarationFile string,
+	moduleName string,
+	filePath string,
+	imported map[string]bool,
+) ([]ModuleCandidate, bool) {
+	var candidates []ModuleCandidate
+	for _, provider := range a.projectExports()[name] {
+		if provider.declarationFile != declarationFile {
+			continue
+		}
+		if provider.module.Name == moduleName && provider.module.FilePath == a.relativePath(filePath) {
+			continue
+		}
+		if provider.global || imported[provider.module.Name] {
+			return nil, true
+		}
+		candidates = append(candidates, provider.module)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].FilePath != candidates[j].FilePath {
+			return candidates[i].FilePath < candidates[j].FilePath
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates, false
+}
+
+// missingImportFinding describes a missing import and its candidate modules
+func missingImportFinding(name string, candidates []ModuleCandidate) Finding {
+	message := fmt.Sprintf("%s is injected but %s, which exports it, is not imported", name, candidates[0].Name)
+	if len(candidates) > 1 {
+		var names []string
+		for _, candidate := range candidates {
+			names = append(names, candidate.Name)
+		}
+		message = fmt.Sprintf("%s is injected but no imported module exports it; candidates: %s", name, strings.Join(names, ", "))
+	}
+	return Finding{
+		RuleID:     RuleMissingImport,
+		Name:       name,
+		Message:    message,
+		Candidates: candidates,
+	}
+}
+
+// projectExports returns the index of providers exported by the modules of
+// the project, building it on first use
+func (a *Analyzer) projectExports() map[string][]exportedProvider {
+	a.exportIndexOnce.Do(func() {
+		a.exportIndex = a.buildExportIndex()
+	})
+	return a.exportIndex
+}
+
+// buildExportIndex indexes the exports of every module file under the
+// working directory by provider name
+func (a *Analyzer) buildExportIndex() map[string][]exportedProvider {
+	index := make(map[string][]exportedProvider)
+	files, err := filesystem.FindModuleFiles(a.options.WorkingDirectory)
+	if err != nil {
+		return index
+	}
+
+	for _, file := range files {
+		exportsByModule, err := a.parser.GetExportsByModule(file)
+		if err != nil || len(exportsByModule) == 0 {
+			continue
+		}
+		importPaths, err := a.parser.GetImportPaths(file)
+		if err != nil {
+			continue
+		}
+		globals := make(map[string]bool)
+		if globalModules, err := a.parser.GetGlobalModules(file); err == nil {
+			for _, name := range globalModules {
+				globals[name] = true
+			}
+		}
+
+		for moduleName, exports := range exportsByModule {
+			for _, name := range exports {
+				if IsStringTokenKey(name) {
+					continue
+				}
+				declarationFile := a.resolveDeclarationFile(name, importPaths, file)
+				if declarationFile == "" {
+					continue
+				}
+				index[name] = append(index[name], exportedProvider{
+					module: ModuleCandidate{
+						Name:     moduleName,
+						FilePath: a.relativePath(file),
+					},
+					declarationFile: declarationFile,
+					global:          globals[moduleName],
+				})
+			}
+		}
+	}
+	return index
+}
+
+// relativePath converts a path to be relative to the working directory
+func (a *Analyzer) relativePath(path string) string {
+	relativePath, err := filepath.Rel(a.options.WorkingDirectory, path)
+	if err != nil {
+		return path
+	}
+	return relativePath
+}
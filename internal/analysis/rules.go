@@ -0,0 +1,69 @@
+package analysis
+
+// Rule identifiers reported by the analyzer
+const (
+	RuleUnusedImport        = "unused-import"
+	RuleUnusedFeatureEntity = "unused-feature-entity"
+	RuleUnusedQueue         = "unused-queue"
+	RuleUnregisteredQueue   = "unregistered-queue"
+	RuleMissingImport       = "missing-import"
+)
+
+// Severities of rules. Errors are issues that fail at runtime, e.g. a
+// provider Nest cannot resolve; warnings are dead code.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rule describes a check performed by the analyzer
+type Rule struct {
+	ID          string
+	Title       string
+	Description string
+	Severity    string
+}
+
+// Rules lists every rule the analyzer can report
+var Rules = []Rule{
+	{
+		ID:          RuleUnusedImport,
+		Title:       "Unused Imports",
+		Description: "A module listed in @Module() imports whose exports are not used by the module's providers or controllers",
+		Severity:    SeverityWarning,
+	},
+	{
+		ID:          RuleUnusedFeatureEntity,
+		Title:       "Unused Feature Entities",
+		Description: "An entity or model registered with TypeOrmModule, MongooseModule or SequelizeModule forFeature() that no provider injects",
+		Severity:    SeverityWarning,
+	},
+	{
+		ID:          RuleUnusedQueue,
+		Title:       "Unused Queues",
+		Description: "A queue registered with BullModule.registerQueue() that no provider injects with @InjectQueue() or processes with @Processor()",
+		Severity:    SeverityWarning,
+	},
+	{
+		ID:          RuleUnregisteredQueue,
+		Title:       "Unregistered Queues",
+		Description: "A queue injected with @InjectQueue() that neither the module nor the modules it imports register",
+		Severity:    SeverityError,
+	},
+	{
+		ID:          RuleMissingImport,
+		Title:       "Missing Imports",
+		Description: "A provider injected by the module's providers that is exported by a module the module does not import",
+		Severity:    SeverityError,
+	},
+}
+
+// RuleByID looks up a rule by its identifier
+func RuleByID(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import "strings"
+
+// StringTokenKey returns the key used to compare string injection tokens by
+// value. It is quoted so it cannot collide with identifier names.
+func StringTokenKey(value string) string {
+	return "'" + value + "'"
+}
+
+// queueKey returns the key of a queue among the exports of imported modules
+// and the tokens providers use. Queues have keys of their own so they cannot
+// match an injection token of the same name.
+func queueKey(tokenKey string) string {
+	return queueKeyPrefix + tokenKey
+}
+
+const queueKeyPrefix = "queue:"
+
+// declaredTokenKey returns the key of a const token without a string value,
+// e.g. a Symbol(), which is only the same token as itself. It names the file
+// declaring the const, so consts of the same name in other files differ.
+func declaredTokenKey(filePath, name string) string {
+	return declaredTokenKeyPrefix + filePath + "#" + name
+}
+
+const declaredTokenKeyPrefix = "const:"
+
+// keyDisplayName returns an export or token key as it reads in messages,
+// e.g. queue 'mail' for the key of the mail queue
+func keyDisplayName(key string) string {
+	if tokenKey, ok := strings.CutPrefix(key, queueKeyPrefix); ok {
+		return "queue " + keyDisplayName(tokenKey)
+	}
+	if declaration, ok := strings.CutPrefix(key, declaredTokenKeyPrefix); ok {
+		return declaration[strings.LastIndex(declaration, "#")+1:]
+	}
+	return key
+}
+
+// IsStringTokenKey reports whether a token key was built from a string literal
+func IsStringTokenKey(token string) bool {
+	return strings.HasPrefix(token, "'")
+}
+
+// TokenDisplayName returns a token key as written, without the quotes of
+// string token keys
+func TokenDisplayName(token string) string {
+	if IsStringTokenKey(token) {
+		return strings.Trim(token, "'")
+	}
+	return token
+}
@@ -1,19 +1,185 @@
 package analysis
 
+import "fmt"
+
 // ModuleAnalysisResult represents the result of analyzing a single module
 type ModuleAnalysisResult struct {
-	ModuleName        string   `json:"module_name"`
-	FilePath          string   `json:"file_path"`
-	UnusedImports     []string `json:"unused_imports"`
-	IgnoredImports    []string `json:"ignored_imports,omitempty"`
-	ReExportedImports []string `json:"reexported_imports,omitempty"`
+	ModuleName        string    `json:"module_name"`
+	FilePath          string    `json:"file_path"`
+	UnusedImports     []string  `json:"unused_imports"`
+	IgnoredImports    []string  `json:"ignored_imports,omitempty"`
+	ReExportedImports []string  `json:"reexported_imports,omitempty"`
+	Findings          []Finding `json:"findings,omitempty"`
+	// UsedImports explains why each of the other imports counts as used
+	UsedImports []ImportUsage `json:"used_imports,omitempty"`
+	// Location is the module class name in its declaration
+	Location *Location `json:"location,omitempty"`
+	// ImportLocations holds the imports elements of the used, unused,
+	// ignored and re-exported imports by name
+	ImportLocations map[string]Location `json:"import_locations,omitempty"`
+	// Metadata is set with AnalysisOptions.IncludeModuleMetadata
+	Metadata *ModuleMetadata `json:"metadata,omitempty"`
+}
+
+// ImportUsage explains why an import counts as used
+type ImportUsage struct {
+	Name string `json:"name"`
+	// Provider is the provider or controller using Symbol. It is empty when
+	// the module's own provider definitions inject Symbol, e.g. through a
+	// useFactory inject array.
+	Provider string `json:"provider,omitempty"`
+	// Symbol is the export of the imported module that is used, or a queue
+	// it registers, e.g. queue 'mail'. Provider and Symbol are both empty
+	// when the import could not be analyzed.
+	Symbol string `json:"symbol,omitempty"`
+	// External is set for modules imported from packages, which are not
+	// analyzed and count as used
+	External bool `json:"external,omitempty"`
+}
+
+// ModuleMetadata lists the elements of a module's @Module() metadata
+type ModuleMetadata struct {
+	Imports   []string `json:"imports"`
+	Providers []string `json:"providers"`
+	Exports   []string `json:"exports"`
+	// ImportFiles maps the imports declared in project files to those files,
+	// relative to the working directory. Modules imported from packages are
+	// left out.
+	ImportFiles map[string]string `json:"import_files"`
+}
+
+// HasFindings reports whether any rule flagged the module
+func (r *ModuleAnalysisResult) HasFindings() bool {
+	return len(r.UnusedImports) > 0 || len(r.Findings) > 0
+}
+
+// AllFindings returns the unused imports as findings of RuleUnusedImport,
+// followed by the findings of the other rules
+func (r *ModuleAnalysisResult) AllFindings() []Finding {
+	findings := make([]Finding, 0, len(r.UnusedImports)+len(r.Findings))
+	for _, name := range r.UnusedImports {
+		finding := Finding{
+			RuleID:   RuleUnusedImport,
+			Name:     name,
+			Message:  fmt.Sprintf("%s is imported but no provider or controller of %s uses anything it exports", name, r.ModuleName),
+			Location: r.Location,
+		}
+		if location, ok := r.ImportLocations[name]; ok {
+			finding.Location = &location
+		}
+		findings = append(findings, finding)
+	}
+	return append(findings, r.Findings...)
+}
+
+// FindingsByRule returns the findings reported by the given rule
+func (r *ModuleAnalysisResult) FindingsByRule(ruleID string) []Finding {
+	return FindingsByRule(r.Findings, ruleID)
+}
+
+// FindingsByRule returns the findings of a list reported by the given rule
+func FindingsByRule(findings []Finding, ruleID string) []Finding {
+	var filtered []Finding
+	for _, finding := range findings {
+		if finding.RuleID == ruleID {
+			filtered = append(filtered, finding)
+		}
+	}
+	return filtered
+}
+
+// Finding is a rule violation within a module. Unused imports are reported
+// in ModuleAnalysisResult.UnusedImports, every other rule reports findings.
+type Finding struct {
+	RuleID  string `json:"rule_id"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	// Candidates lists the modules that could satisfy a missing import
+	Candidates []ModuleCandidate `json:"candidates,omitempty"`
+	// Location is the element the finding is about, or the module class name
+	// when the finding has no element, e.g. a missing import
+	Location *Location `json:"location,omitempty"`
+}
+
+// Location is a range of source code. Lines and columns start at 1 and
+// columns count bytes; byte offsets start at 0. The end is exclusive.
+type Location struct {
+	StartLine   int `json:"start_line"`
+	StartColumn int `json:"start_column"`
+	EndLine     int `json:"end_line"`
+	EndColumn   int `json:"end_column"`
+	StartByte   int `json:"start_byte"`
+	EndByte     int `json:"end_byte"`
+}
+
+// Element kinds of ModuleLocations besides the imports, providers and
+// exports metadata keys
+const (
+	// ElementEntities are the entities registered with ORM forFeature() calls
+	ElementEntities = "entities"
+	// ElementQueues are the options objects registering Bull queues
+	ElementQueues = "queues"
+)
+
+// ModuleLocations holds where a module class and the elements of its
+// metadata are declared
+type ModuleLocations struct {
+	Declaration Location
+	// Elements maps imports, providers, exports, ElementEntities and
+	// ElementQueues to the first element of each name
+	Elements map[string]map[string]Location
+}
+
+// Element returns the location of the element listed under key by name
+func (l ModuleLocations) Element(key, name string) (Location, bool) {
+	location, ok := l.Elements[key][name]
+	return location, ok
+}
+
+// ModuleCandidate is a module that exports a provider another module needs
+type ModuleCandidate struct {
+	Name     string `json:"name"`
+	FilePath string `json:"file_path"`
+}
+
+// FeatureEntity is an entity or model registered with an ORM module's
+// forFeature(), e.g. User in TypeOrmModule.forFeature([User])
+type FeatureEntity struct {
+	Module string
+	Name   string
+}
+
+// BullModuleName is the module that registers Bull queues. Modules that
+// register queues make them available to importers by exporting it.
+const BullModuleName = "BullModule"
+
+// Decorators that use a Bull queue
+const (
+	QueueInjectDecorator    = "InjectQueue"
+	QueueProcessorDecorator = "Processor"
+)
+
+// QueueUsage is a queue injected with @InjectQueue() or consumed with
+// @Processor(). Name is a token key: an identifier name or a string token key.
+type QueueUsage struct {
+	Name      string
+	Decorator string
 }
 
 // AnalysisOptions contains configuration for the analysis
 type AnalysisOptions struct {
-	WorkingDirectory string
-	EnableIgnores    bool
-	EnableReExports  bool
+	WorkingDirectory      string
+	EnableIgnores         bool
+	EnableReExports       bool
+	EnableFeatureEntities bool
+	EnableQueues          bool
+	EnableMissingImports  bool
+	// IncludeCleanModules returns results for the analyzed modules without
+	// findings too, e.g. for reports that list passing modules
+	IncludeCleanModules bool
+	// IncludeModuleMetadata records the imports, providers and exports of
+	// each module, e.g. for reports that draw the module graph
+	IncludeModuleMetadata bool
 }
 
 // ModuleInfo contains basic information about a module
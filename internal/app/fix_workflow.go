@@ -2,56 +2,130 @@ package app
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
 )
 
-// FixWorkflow handles the complete fix process for a directory or file
-func FixWorkflow(path string) error {
-	// First, analyze to find unused imports
-	reports, err := AnalyzePath(path)
+// FixWorkflow handles the complete fix process for directories or files and
+// returns what changed in each file. The paths are fixed as one run, which
+// is journaled so UndoLastFix can revert it. Inside a git work tree, files
+// with uncommitted changes are left unchanged unless opts.AllowDirty is set.
+func FixWorkflow(paths []string, opts Options) ([]*fixing.FixResult, error) {
+	analyzer, tsPathResolver, err := newAnalyzer(opts)
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
-	if len(reports) == 0 {
-		fmt.Println("✓ No unused imports found - nothing to fix")
-		return nil
+	journalDir, err := journalDir()
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Found %d files with unused imports, fixing...\n", len(reports))
+	fixer := fixing.NewFixer(getTypescriptLanguage(), opts.ModuleDecorators...)
+	workflow := fixing.NewWorkflow(analyzer, fixer, tsPathResolver).WithJournal(journalDir).WithSelector(opts.FixSelector).WithWriteGuard(writeGuard(opts))
+	return workflow.FixPaths(paths)
+}
 
-	// Fix each file
-	for _, report := range reports {
-		err := fixFileUnusedImports(report.Path, report.UnnecessaryImports)
-		if err != nil {
-			return fmt.Errorf("failed to fix %s: %w", report.Path, err)
-		}
-		fmt.Printf("✓ Fixed %s (removed: %v)\n", report.Path, report.UnnecessaryImports)
+// FixInteractive asks for a decision on each unused import of directories
+// or files, reading answers from in and writing prompts to out, and applies
+// the accepted fixes as one run
+func FixInteractive(paths []string, opts Options, in io.Reader, out io.Writer) ([]*fixing.FixResult, error) {
+	analyzer, tsPathResolver, err := newAnalyzer(opts)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	journalDir, err := journalDir()
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("✓ Successfully fixed %d files\n", len(reports))
-	return nil
+	fixer := fixing.NewFixer(getTypescriptLanguage(), opts.ModuleDecorators...)
+	workflow := fixing.NewWorkflow(analyzer, fixer, tsPathResolver).WithJournal(journalDir).WithSelector(opts.FixSelector).WithWriteGuard(writeGuard(opts))
+	return workflow.FixInteractive(paths, fixing.NewPrompter(in, out))
 }
 
-// fixFileUnusedImports fixes unused imports in a specific file
-func fixFileUnusedImports(filePath string, unusedModules []string) error {
-	// Read the current file
-	sourceCode, err := os.ReadFile(filePath)
+// PlanFixes computes the fixes for a directory or file without writing them
+func PlanFixes(path string, opts Options) (*fixing.Plan, error) {
+	analyzer, tsPathResolver, err := newAnalyzer(opts)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
-	// Apply fixes
-	fixedCode, err := FixUnusedImports(sourceCode, unusedModules)
+	fixer := fixing.NewFixer(getTypescriptLanguage(), opts.ModuleDecorators...)
+	return fixing.NewWorkflow(analyzer, fixer, tsPathResolver).WithSelector(opts.FixSelector).Plan(path)
+}
+
+// PlanFindingFixes computes the fix of each fixable finding of a set of
+// analysis results on its own, for reports that describe fixes
+func PlanFindingFixes(results []*analysis.ModuleAnalysisResult, opts Options) ([]fixing.FindingFix, error) {
+	analyzer, tsPathResolver, err := newAnalyzer(opts)
 	if err != nil {
-		return fmt.Errorf("failed to apply fixes: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
-	// Write the fixed code back
-	err = os.WriteFile(filePath, fixedCode, 0644)
+	fixer := fixing.NewFixer(getTypescriptLanguage(), opts.ModuleDecorators...)
+	return fixing.NewWorkflow(analyzer, fixer, tsPathResolver).PlanFindingFixes(results)
+}
+
+// UndoLastFix restores the files changed by the last fix run in the working
+// directory and returns their paths
+func UndoLastFix() ([]string, error) {
+	journalDir, err := journalDir()
 	if err != nil {
-		return fmt.Errorf("failed to write fixed file: %w", err)
+		return nil, err
 	}
+	return fixing.UndoLastFix(journalDir)
+}
 
-	return nil
+// journalDir returns the fix journal directory of the working directory
+func journalDir() (string, error) {
+	cwd, err := getWorkingDirectory()
+	if err != nil {
+		return "", err
+	}
+	return fixing.JournalDir(cwd)
+}
+
+// PrettyPrintFixResult formats what a fix run changed in a file
+func PrettyPrintFixResult(result *fixing.FixResult) string {
+	builder := strings.Builder{}
+	switch {
+	case result.Fixed:
+		builder.WriteString(fmt.Sprintf("✓ Fixed %s\n", result.File))
+	case result.Error != "":
+		builder.WriteString(fmt.Sprintf("✗ Not fixed %s\n", result.File))
+	default:
+		builder.WriteString(fmt.Sprintf("⚠ Skipped findings in %s\n", result.File))
+	}
+
+	for _, element := range result.RemovedElements {
+		builder.WriteString(fmt.Sprintf("\tremoved %s from %s\n", element.Name, element.Module))
+	}
+	for _, element := range result.AddedElements {
+		builder.WriteString(fmt.Sprintf("\tadded %s to %s\n", element.Name, element.Module))
+	}
+	for _, element := range result.IgnoredElements {
+		builder.WriteString(fmt.Sprintf("\tignored %s in %s\n", element.Name, element.Module))
+	}
+	for _, statement := range result.ImportStatements {
+		var names []string
+		for _, name := range statement.Removed {
+			names = append(names, "-"+name)
+		}
+		for _, name := range statement.Added {
+			names = append(names, "+"+name)
+		}
+		builder.WriteString(fmt.Sprintf("\t%s import from '%s' (%s)\n", statement.Action, statement.Specifier, strings.Join(names, ", ")))
+	}
+	for _, skipped := range result.Skipped {
+		builder.WriteString(fmt.Sprintf("\tskipped %s in %s: %s\n", skipped.Name, skipped.Module, skipped.Reason))
+	}
+	if result.Error != "" {
+		builder.WriteString(fmt.Sprintf("\terror: %s\n", result.Error))
+	}
+	return builder.String()
 }
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/git"
+)
+
+// writeGuard returns the guard refusing to fix files with uncommitted
+// changes in a git work tree, or nil when every file may be written. Files
+// outside of git work trees may always be written.
+func writeGuard(opts Options) fixing.WriteGuard {
+	if opts.AllowDirty {
+		return nil
+	}
+
+	return func(paths []string) (map[string]string, error) {
+		groups, _, err := groupByRepository(paths)
+		if err != nil {
+			return nil, err
+		}
+
+		refused := make(map[string]string)
+		for _, group := range groups {
+			dirty, err := group.repo.DirtyFiles(group.files)
+			if err != nil {
+				return nil, err
+			}
+			for _, file := range dirty {
+				refused[file] = "refusing to modify a file with uncommitted changes (use --allow-dirty)"
+			}
+		}
+		return refused, nil
+	}
+}
+
+// repositoryFiles are files of the same git work tree
+type repositoryFiles struct {
+	repo  *git.Repository
+	files []string
+}
+
+// groupByRepository groups paths by the git work tree holding them, in
+// order of their roots, since the files of a run can belong to several work
+// trees. Paths outside of git work trees are returned separately.
+func groupByRepository(paths []string) ([]repositoryFiles, []string, error) {
+	groupsByRoot := make(map[string]*repositoryFiles)
+	var roots, outside []string
+	for _, path := range paths {
+		repo, err := git.Open(path)
+		if errors.Is(err, git.ErrNotRepository) {
+			outside = append(outside, path)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		group, ok := groupsByRoot[repo.Root()]
+		if !ok {
+			group = &repositoryFiles{repo: repo}
+			groupsByRoot[repo.Root()] = group
+			roots = append(roots, repo.Root())
+		}
+		group.files = append(group.files, path)
+	}
+
+	sort.Strings(roots)
+	groups := make([]repositoryFiles, len(roots))
+	for i, root := range roots {
+		groups[i] = *groupsByRoot[root]
+	}
+	return groups, outside, nil
+}
+
+// CommitFixes commits the files a fix run changed, with a message listing
+// the changes. Files of several git work trees are committed to each work
+// tree, with the changes of its files. It reports whether a commit was made.
+func CommitFixes(results []*fixing.FixResult) (bool, error) {
+	resultsByFile := make(map[string]*fixing.FixResult)
+	var files []string
+	for _, result := range results {
+		if result.Fixed {
+			files = append(files, result.File)
+			resultsByFile[result.File] = result
+		}
+	}
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	// Every file must be committable before the first commit is made
+	groups, outside, err := groupByRepository(files)
+	if err != nil {
+		return false, fmt.Errorf("cannot commit fixes: %w", err)
+	}
+	if len(outside) > 0 {
+		return false, fmt.Errorf("cannot commit fixes to %s: %w", outside[0], git.ErrNotRepository)
+	}
+
+	var committed []string
+	for _, group := range groups {
+		groupResults := make([]*fixing.FixResult, len(group.files))
+		for i, file := range group.files {
+			groupResults[i] = resultsByFile[file]
+		}
+		if err := group.repo.Commit(fixing.CommitMessage(groupResults), group.files); err != nil {
+			if len(committed) > 0 {
+				return true, fmt.Errorf("failed to commit fixes in %s after committing them in %s: %w", group.repo.Root(), strings.Join(committed, ", "), err)
+			}
+			return false, fmt.Errorf("failed to commit fixes in %s: %w", group.repo.Root(), err)
+		}
+		committed = append(committed, group.repo.Root())
+	}
+	return true, nil
+}
+
+// WritePatch writes the changes of a fix run as a patch file, headed by the
+// generated commit message as comment lines
+func WritePatch(patchFile string, results []*fixing.FixResult) error {
+	var diff strings.Builder
+	for _, result := range results {
+		diff.WriteString(result.Diff)
+	}
+
+	var patch strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(fixing.CommitMessage(results), "\n"), "\n") {
+		patch.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	patch.WriteString("\n")
+	patch.WriteString(diff.String())
+	return filesystem.WriteFileAtomic(patchFile, []byte(patch.String()))
+}
@@ -0,0 +1,87 @@
+package app_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/app"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+)
+
+// newRepository creates a git work tree with a committed module file and
+// returns the file
+func newRepository(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		runGit(t, dir, args...)
+	}
+	file := filepath.Join(dir, "app.module.ts")
+	if err := os.WriteFile(file, []byte("original\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "--quiet", "-m", "initial")
+	return file
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+func TestCommitFixes_SeveralRepositories(t *testing.T) {
+	var results []*fixing.FixResult
+	for _, module := range []string{"AppModule", "UsersModule"} {
+		file := newRepository(t)
+		if err := os.WriteFile(file, []byte("fixed\n"), 0644); err != nil {
+			t.Fatalf("Failed to edit test file: %v", err)
+		}
+		results = append(results, &fixing.FixResult{
+			File:  file,
+			Fixed: true,
+			RemovedElements: []fixing.ElementChange{
+				{Module: module, Name: "UnusedModule", Kind: fixing.ElementImport},
+			},
+		})
+	}
+
+	committed, err := app.CommitFixes(results)
+	if err != nil {
+		t.Fatalf("CommitFixes failed: %v", err)
+	}
+	if !committed {
+		t.Fatal("Expected the fixes to be committed")
+	}
+
+	// Each work tree gets a commit of its own file, listing its own changes
+	for i, result := range results {
+		dir := filepath.Dir(result.File)
+		if status := runGit(t, dir, "status", "--porcelain"); status != "" {
+			t.Errorf("Expected %s to be committed, got status:\n%s", dir, status)
+		}
+		message := runGit(t, dir, "log", "-1", "--format=%B")
+		other := results[1-i].RemovedElements[0].Module
+		if !strings.Contains(message, result.RemovedElements[0].Module) || strings.Contains(message, other) {
+			t.Errorf("Expected the commit in %s to list only its changes, got:\n%s", dir, message)
+		}
+	}
+}
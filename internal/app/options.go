@@ -0,0 +1,21 @@
+package app
+
+import "github.com/evanrichards/nestjs-module-lint/internal/fixing"
+
+// Options configures how modules are analyzed and fixed
+type Options struct {
+	// ModuleDecorators lists custom decorators that wrap @Module() metadata,
+	// e.g. FeatureModule for @FeatureModule({...})
+	ModuleDecorators []string
+	// FixSelector limits fixes to the findings it matches
+	FixSelector fixing.Selector
+	// AllowDirty lets fixes modify files with uncommitted changes in a git
+	// work tree
+	AllowDirty bool
+
+	// includeCleanModules makes the analyzer return modules without findings
+	includeCleanModules bool
+	// includeModuleMetadata makes the analyzer record the imports, providers
+	// and exports of each module
+	includeModuleMetadata bool
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
 	"github.com/evanrichards/nestjs-module-lint/internal/detection"
 	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
 	"github.com/evanrichards/nestjs-module-lint/internal/resolver"
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/typescript/typescript"
@@ -23,24 +24,24 @@ func getWorkingDirectory() (string, error) {
 	return os.Getwd()
 }
 
-// AnalyzePath analyzes a file or directory for unused module imports
-// This is the main entry point using the new analysis architecture
-func AnalyzePath(path string) ([]*ModuleReport, error) {
+// newAnalyzer wires the analyzer with the parser, resolver and detectors. The
+// tsconfig path resolver is returned as well for fixes that add imports.
+func newAnalyzer(opts Options) (*analysis.Analyzer, *resolver.TsPathResolver, error) {
 	// Get current working directory
 	cwd, err := getWorkingDirectory()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Create path resolver
 	tsPathResolver, err := resolver.NewTsPathResolverFromPath(cwd)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	pathResolverAdapter := resolver.NewPathResolverAdapter(tsPathResolver)
 
 	// Create parser adapter
-	parserAdapter := parser.NewParserAdapter(getTypescriptLanguage())
+	parserAdapter := parser.NewParserAdapter(getTypescriptLanguage(), opts.ModuleDecorators...)
 
 	// Create detection adapters
 	ignoreDetector := detection.NewIgnoreDetector()
@@ -51,19 +52,64 @@ func AnalyzePath(path string) ([]*ModuleReport, error) {
 
 	// Create analysis options
 	options := analysis.AnalysisOptions{
-		WorkingDirectory: cwd,
-		EnableIgnores:    true,
-		EnableReExports:  true,
+		WorkingDirectory:      cwd,
+		EnableIgnores:         true,
+		EnableReExports:       true,
+		EnableFeatureEntities: true,
+		EnableQueues:          true,
+		EnableMissingImports:  true,
+		IncludeCleanModules:   opts.includeCleanModules,
+		IncludeModuleMetadata: opts.includeModuleMetadata,
 	}
 
 	// Create analyzer
-	analyzer := analysis.NewAnalyzer(
+	return analysis.NewAnalyzer(
 		parserAdapter,
 		pathResolverAdapter,
 		ignoreAdapter,
 		reExportAdapter,
 		options,
-	)
+	), tsPathResolver, nil
+}
+
+// AnalyzePath analyzes a file or directory for unused module imports
+// This is the main entry point using the new analysis architecture
+func AnalyzePath(path string, opts Options) ([]*ModuleReport, error) {
+	results, err := AnalyzeResults(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ModuleReports(results), nil
+}
+
+// ModuleReports converts the analysis results of the modules with findings
+// to module reports
+func ModuleReports(results []*analysis.ModuleAnalysisResult) []*ModuleReport {
+	var reports []*ModuleReport
+	for _, result := range results {
+		if result.HasFindings() {
+			reports = append(reports, &ModuleReport{
+				ModuleName:         result.ModuleName,
+				Path:               result.FilePath,
+				UnnecessaryImports: result.UnusedImports,
+				Findings:           result.Findings,
+				Location:           result.Location,
+				ImportLocations:    unusedImportLocations(result),
+			})
+		}
+	}
+	return reports
+}
+
+// AnalyzeResults analyzes a file or directory and returns the analysis
+// results of every module, including those without findings, for the
+// reporting formatter
+func AnalyzeResults(path string, opts Options) ([]*analysis.ModuleAnalysisResult, error) {
+	opts.includeCleanModules = true
+	analyzer, _, err := newAnalyzer(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine if we're analyzing a file or directory
 	info, err := os.Stat(path)
@@ -81,33 +127,66 @@ func AnalyzePath(path string) ([]*ModuleReport, error) {
 	if err != nil {
 		return nil, err
 	}
+	return results, nil
+}
 
-	// Convert analysis results to ModuleReport for backward compatibility
-	var reports []*ModuleReport
-	for _, result := range results {
-		if len(result.UnusedImports) > 0 {
-			reports = append(reports, &ModuleReport{
-				ModuleName:         result.ModuleName,
-				Path:               result.FilePath,
-				UnnecessaryImports: result.UnusedImports,
-			})
-		}
-	}
-
-	return reports, nil
+// AnalyzeModuleGraph analyzes a file or directory like AnalyzeResults,
+// recording the metadata of every module for the module graph report
+func AnalyzeModuleGraph(path string, opts Options) ([]*analysis.ModuleAnalysisResult, error) {
+	opts.includeModuleMetadata = true
+	return AnalyzeResults(path, opts)
 }
 
 type ModuleReport struct {
-	ModuleName         string   `json:"module_name"`
-	Path               string   `json:"path"`
-	UnnecessaryImports []string `json:"unnecessary_imports"`
+	ModuleName         string             `json:"module_name"`
+	Path               string             `json:"path"`
+	UnnecessaryImports []string           `json:"unnecessary_imports"`
+	Findings           []analysis.Finding `json:"findings,omitempty"`
+	// Location is the module class name in its declaration
+	Location *analysis.Location `json:"location,omitempty"`
+	// ImportLocations holds the imports elements of the unnecessary imports
+	// by name
+	ImportLocations map[string]analysis.Location `json:"import_locations,omitempty"`
+}
+
+// unusedImportLocations returns the locations of a module's unused imports
+func unusedImportLocations(result *analysis.ModuleAnalysisResult) map[string]analysis.Location {
+	var locations map[string]analysis.Location
+	for _, name := range result.UnusedImports {
+		location, ok := result.ImportLocations[name]
+		if !ok {
+			continue
+		}
+		if locations == nil {
+			locations = make(map[string]analysis.Location)
+		}
+		locations[name] = location
+	}
+	return locations
 }
 
 func PrettyPrintModuleReport(report *ModuleReport) string {
 	builder := strings.Builder{}
-	builder.WriteString(fmt.Sprintf("Module: %s\nPath: %s\nUnnecessary Imports:\n", report.ModuleName, report.Path))
-	for _, imp := range report.UnnecessaryImports {
-		builder.WriteString(fmt.Sprintf("\t%s\n", imp))
+	builder.WriteString(fmt.Sprintf("Module: %s\nPath: %s\n", report.ModuleName, reporting.FormatPath(report.Path, report.Location)))
+	if len(report.UnnecessaryImports) > 0 {
+		builder.WriteString("Unnecessary Imports:\n")
+		for _, imp := range report.UnnecessaryImports {
+			var position string
+			if location, ok := report.ImportLocations[imp]; ok {
+				position = reporting.FormatLocation(&location)
+			}
+			builder.WriteString(fmt.Sprintf("\t%s%s\n", imp, position))
+		}
+	}
+	for _, rule := range analysis.Rules {
+		findings := analysis.FindingsByRule(report.Findings, rule.ID)
+		if len(findings) == 0 {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%s:\n", rule.Title))
+		for _, finding := range findings {
+			builder.WriteString(fmt.Sprintf("\t%s - %s%s\n", finding.Name, finding.Message, reporting.FormatLocation(finding.Location)))
+		}
 	}
 	return builder.String()
 }
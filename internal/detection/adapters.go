@@ -24,6 +24,11 @@ func (a *IgnoreDetectorAdapter) ShouldIgnoreImport(moduleName string, source []b
 	return a.detector.ShouldIgnoreImport(moduleName, source)
 }
 
+// ShouldIgnoreLine implements the analysis.IgnoreDetector interface
+func (a *IgnoreDetectorAdapter) ShouldIgnoreLine(line int, source []byte) bool {
+	return a.detector.ShouldIgnoreLine(line, source)
+}
+
 // ReExportDetectorAdapter adapts ReExportDetector to implement the analysis.ReExportDetector interface
 type ReExportDetectorAdapter struct {
 	detector *ReExportDetector
@@ -39,6 +39,12 @@ func (d *IgnoreDetector) ShouldIgnoreImport(moduleName string, source []byte) bo
 	return info.ShouldIgnoreModule(moduleName)
 }
 
+// ShouldIgnoreLine checks if a line (1-based) has a disable-line comment
+func (d *IgnoreDetector) ShouldIgnoreLine(line int, source []byte) bool {
+	info := d.ParseIgnoreComments(source)
+	return info.FileIgnored || info.IgnoredLines[line]
+}
+
 // ParseIgnoreComments analyzes source code for ignore comments
 func (d *IgnoreDetector) ParseIgnoreComments(sourceCode []byte) *IgnoreInfo {
 	source := string(sourceCode)
@@ -123,6 +123,34 @@ func TestIgnoreDetector_ShouldIgnoreFile(t *testing.T) {
 	}
 }
 
+func TestIgnoreDetector_ShouldIgnoreLine(t *testing.T) {
+	detector := detection.NewIgnoreDetector()
+	sourceCode := `@Module({
+  imports: [
+    BullModule.registerQueue({ name: 'emails' }),
+    BullModule.registerQueue({ name: 'reports' }), // nestjs-module-lint-disable-line
+  ],
+})`
+
+	tests := []struct {
+		name     string
+		line     int
+		expected bool
+	}{
+		{name: "line with ignore comment", line: 4, expected: true},
+		{name: "line without ignore comment", line: 3, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.ShouldIgnoreLine(tt.line, []byte(sourceCode))
+			if result != tt.expected {
+				t.Errorf("ShouldIgnoreLine(%d) = %v, want %v", tt.line, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIgnoreDetector_GetNonIgnoredImports(t *testing.T) {
 	detector := detection.NewIgnoreDetector()
 
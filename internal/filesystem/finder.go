@@ -42,3 +42,38 @@ func isTypeScriptFile(path string) bool {
 	lower := strings.ToLower(path)
 	return strings.HasSuffix(lower, ".ts") || strings.HasSuffix(lower, ".tsx")
 }
+
+// moduleFileSuffix is the NestJS naming convention for module files
+const moduleFileSuffix = ".module.ts"
+
+// FindModuleFiles recursively finds the NestJS module files (*.module.ts) in
+// a directory, skipping node_modules and hidden directories
+func FindModuleFiles(root string) ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(strings.ToLower(path), moduleFileSuffix) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -73,3 +73,43 @@ func TestFindTypeScriptFiles_NonExistentDirectory(t *testing.T) {
 		t.Error("Expected error for non-existent directory")
 	}
 }
+
+func TestFindModuleFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFiles := []string{
+		"app.module.ts",
+		"users/users.module.ts",
+		"users/users.service.ts",
+		"node_modules/@nestjs/config/config.module.ts",
+		".cache/stale.module.ts",
+	}
+
+	for _, file := range testFiles {
+		path := filepath.Join(tempDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte("// test file"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", path, err)
+		}
+	}
+
+	files, err := filesystem.FindModuleFiles(tempDir)
+	if err != nil {
+		t.Fatalf("FindModuleFiles failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(tempDir, "app.module.ts"),
+		filepath.Join(tempDir, "users/users.module.ts"),
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], files[i])
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package filesystem
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchGlob reports whether a slash or OS separated file path matches a glob
+// pattern. Besides the path.Match syntax within a segment, a ** segment
+// matches any number of directories. A pattern without wildcards also
+// matches every path below it, so src/users selects the whole directory.
+func MatchGlob(pattern, filePath string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	filePath = strings.TrimPrefix(filepath.ToSlash(filePath), "./")
+	if !strings.ContainsAny(pattern, "*?[") {
+		pattern = strings.TrimSuffix(pattern, "/")
+		return filePath == pattern || strings.HasPrefix(filePath, pattern+"/")
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	matched, err := path.Match(pattern[0], segments[0])
+	return err == nil && matched && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,30 @@
+package filesystem_test
+
+import (
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{pattern: "src/users/**", path: "src/users/users.module.ts", expected: true},
+		{pattern: "src/users/**", path: "src/users/admin/admin.module.ts", expected: true},
+		{pattern: "src/users/**", path: "src/orders/orders.module.ts", expected: false},
+		{pattern: "src/**/*.module.ts", path: "src/app.module.ts", expected: true},
+		{pattern: "src/*/orders.module.ts", path: "src/a/b/orders.module.ts", expected: false},
+		{pattern: "./src/users", path: "src/users/users.module.ts", expected: true},
+		{pattern: "src/users", path: "src/users-legacy/users.module.ts", expected: false},
+		{pattern: "**/legacy/*.ts", path: "src/team/legacy/auth.module.ts", expected: true},
+	}
+
+	for _, tt := range tests {
+		if matched := filesystem.MatchGlob(tt.pattern, tt.path); matched != tt.expected {
+			t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.path, matched, tt.expected)
+		}
+	}
+}
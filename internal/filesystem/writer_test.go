@@ -0,0 +1,81 @@
+package filesystem_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.module.ts")
+	if err := os.WriteFile(testFile, []byte("original"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if err := filesystem.WriteFileAtomic(testFile, []byte("fixed")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(content) != "fixed" {
+		t.Errorf("Expected content %q, got %q", "fixed", content)
+	}
+
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600 to be kept, got %v", info.Mode().Perm())
+	}
+
+	// No temporary file is left behind
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the written file, got %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomic_Symlink(t *testing.T) {
+	tempDir := t.TempDir()
+	target := filepath.Join(tempDir, "shared", "test.module.ts")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(target, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	link := filepath.Join(tempDir, "test.module.ts")
+	if err := os.Symlink(filepath.Join("shared", "test.module.ts"), link); err != nil {
+		t.Skipf("Symlinks are not supported: %v", err)
+	}
+
+	if err := filesystem.WriteFileAtomic(link, []byte("fixed")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	// The link is kept and its target gets the new contents
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatalf("Failed to stat link: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected %s to still be a symlink, got mode %v", link, info.Mode())
+	}
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	if string(content) != "fixed" {
+		t.Errorf("Expected target content %q, got %q", "fixed", content)
+	}
+}
@@ -0,0 +1,186 @@
+package fixing
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+// diffOp is a single line of a line-based diff. original and fixed are the
+// zero based line indexes the op refers to in each version.
+type diffOp struct {
+	kind     diffOpKind
+	line     string
+	original int
+	fixed    int
+}
+
+// UnifiedDiff returns a unified diff between two versions of a file with
+// three lines of context, or an empty string when they are equal
+func UnifiedDiff(path string, original, fixed []byte) string {
+	if bytes.Equal(original, fixed) {
+		return ""
+	}
+
+	ops := diffLines(splitLines(original), splitLines(fixed))
+
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	for _, hunk := range diffHunks(ops) {
+		writeHunk(&builder, hunk)
+	}
+	return builder.String()
+}
+
+// splitLines splits content into lines that keep their line break
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a line diff from the longest common subsequence of the
+// lines that differ, after trimming the common prefix and suffix
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: diffEqual, line: a[i], original: i, fixed: i})
+	}
+
+	middleA := a[prefix : len(a)-suffix]
+	middleB := b[prefix : len(b)-suffix]
+	lcs := make([][]int, len(middleA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(middleB)+1)
+	}
+	for i := len(middleA) - 1; i >= 0; i-- {
+		for j := len(middleB) - 1; j >= 0; j-- {
+			if middleA[i] == middleB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(middleA) || j < len(middleB) {
+		switch {
+		case i < len(middleA) && j < len(middleB) && middleA[i] == middleB[j]:
+			ops = append(ops, diffOp{kind: diffEqual, line: middleA[i], original: prefix + i, fixed: prefix + j})
+			i++
+			j++
+		case j == len(middleB) || (i < len(middleA) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: diffDelete, line: middleA[i], original: prefix + i, fixed: prefix + j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: diffInsert, line: middleB[j], original: prefix + i, fixed: prefix + j})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, diffOp{
+			kind:     diffEqual,
+			line:     a[len(a)-suffix+k],
+			original: len(a) - suffix + k,
+			fixed:    len(b) - suffix + k,
+		})
+	}
+	return ops
+}
+
+// diffHunks groups changed ops with their surrounding context, joining
+// changes whose context overlaps
+func diffHunks(ops []diffOp) [][]diffOp {
+	var hunks [][]diffOp
+	start, end := -1, -1
+	for i, op := range ops {
+		if op.kind == diffEqual {
+			continue
+		}
+		from := max(i-diffContext, 0)
+		to := min(i+diffContext+1, len(ops))
+		if start >= 0 && from <= end {
+			end = to
+			continue
+		}
+		if start >= 0 {
+			hunks = append(hunks, ops[start:end])
+		}
+		start, end = from, to
+	}
+	if start >= 0 {
+		hunks = append(hunks, ops[start:end])
+	}
+	return hunks
+}
+
+// writeHunk writes a hunk header followed by its lines
+func writeHunk(builder *strings.Builder, hunk []diffOp) {
+	originalCount, fixedCount := 0, 0
+	for _, op := range hunk {
+		if op.kind != diffInsert {
+			originalCount++
+		}
+		if op.kind != diffDelete {
+			fixedCount++
+		}
+	}
+	builder.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+		hunkRange(hunk[0].original, originalCount),
+		hunkRange(hunk[0].fixed, fixedCount)))
+
+	for _, op := range hunk {
+		switch op.kind {
+		case diffEqual:
+			builder.WriteString(" ")
+		case diffDelete:
+			builder.WriteString("-")
+		case diffInsert:
+			builder.WriteString("+")
+		}
+		builder.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			builder.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the one based start line and line count of a hunk side.
+// An empty side refers to the line before the change.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
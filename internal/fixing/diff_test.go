@@ -0,0 +1,80 @@
+package fixing_test
+
+import (
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		fixed    string
+		expected string
+	}{
+		{
+			name:     "no changes",
+			original: "a\nb\n",
+			fixed:    "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "removed line with context",
+			original: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			fixed:    "1\n2\n3\n4\n6\n7\n8\n9\n",
+			expected: `--- a/app.module.ts
++++ b/app.module.ts
+@@ -2,7 +2,6 @@
+ 2
+ 3
+ 4
+-5
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			name:     "changed line without trailing newline",
+			original: "a\nimports: [A, B]",
+			fixed:    "a\nimports: [A]",
+			expected: `--- a/app.module.ts
++++ b/app.module.ts
+@@ -1,2 +1,2 @@
+ a
+-imports: [A, B]
+\ No newline at end of file
++imports: [A]
+\ No newline at end of file
+`,
+		},
+		{
+			name:     "separate hunks",
+			original: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			fixed:    "2\n3\n4\n5\n6\n7\n8\n9\n",
+			expected: `--- a/app.module.ts
++++ b/app.module.ts
+@@ -1,4 +1,3 @@
+-1
+ 2
+ 3
+ 4
+@@ -7,4 +6,3 @@
+ 7
+ 8
+ 9
+-10
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := fixing.UnifiedDiff("app.module.ts", []byte(tt.original), []byte(tt.fixed))
+			if diff != tt.expected {
+				t.Errorf("Diff mismatch\nGot:\n%s\nExpected:\n%s", diff, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package fixing
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Edit replaces the source bytes in [Start, End) with Replacement
+type Edit struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Replacement string `json:"replacement"`
+	Reason      string `json:"reason"`
+}
+
+// MergeEdits sorts a set of edits and merges overlapping deletions, e.g. the
+// shared separator of two adjacent array elements. Insertions sort before
+// edits starting at the same position. Any other overlap is an error.
+func MergeEdits(edits []Edit, sourceLength int) ([]Edit, error) {
+	sorted := append([]Edit{}, edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].End < sorted[j].End
+	})
+
+	var merged []Edit
+	for _, edit := range sorted {
+		if edit.Start < 0 || edit.End > sourceLength || edit.Start > edit.End {
+			return nil, fmt.Errorf("edit [%d, %d) is outside of the source", edit.Start, edit.End)
+		}
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if edit.Start < last.End {
+				if edit.Replacement != "" || last.Replacement != "" {
+					return nil, fmt.Errorf("edits [%d, %d) and [%d, %d) overlap", last.Start, last.End, edit.Start, edit.End)
+				}
+				if edit.End > last.End {
+					last.End = edit.End
+				}
+				if edit.Reason != "" && edit.Reason != last.Reason {
+					last.Reason += "; " + edit.Reason
+				}
+				continue
+			}
+		}
+		merged = append(merged, edit)
+	}
+	return merged, nil
+}
+
+// ApplyEdits applies a set of edits to the source in one pass, merging
+// overlapping deletions first
+func ApplyEdits(source []byte, edits []Edit) ([]byte, error) {
+	if len(edits) == 0 {
+		return source, nil
+	}
+
+	merged, err := MergeEdits(edits, len(source))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(source))
+	position := 0
+	for _, edit := range merged {
+		result = append(result, source[position:edit.Start]...)
+		result = append(result, edit.Replacement...)
+		position = edit.End
+	}
+	return append(result, source[position:]...), nil
+}
+
+// RevertEdits returns the edits that turn the result of applying edits to
+// source back into source
+func RevertEdits(source []byte, edits []Edit) ([]Edit, error) {
+	merged, err := MergeEdits(edits, len(source))
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := make([]Edit, 0, len(merged))
+	shift := 0
+	for _, edit := range merged {
+		start := edit.Start + shift
+		reverted = append(reverted, Edit{
+			Start:       start,
+			End:         start + len(edit.Replacement),
+			Replacement: string(source[edit.Start:edit.End]),
+			Reason:      "revert " + edit.Reason,
+		})
+		shift += len(edit.Replacement) - (edit.End - edit.Start)
+	}
+	return reverted, nil
+}
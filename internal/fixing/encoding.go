@@ -0,0 +1,82 @@
+package fixing
+
+import (
+	"bytes"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// textEncoding records the byte order mark and line endings of a file, so
+// fixes computed on normalized source can be written back in the file's own
+// format
+type textEncoding struct {
+	bom  bool
+	crlf bool
+}
+
+// detectEncoding inspects a file's contents. Line endings count as CRLF only
+// when every line ends with one, so files with mixed endings are left as is.
+func detectEncoding(source []byte) textEncoding {
+	body := bytes.TrimPrefix(source, utf8BOM)
+	lines := bytes.Count(body, []byte("\n"))
+	return textEncoding{
+		bom:  len(body) != len(source),
+		crlf: lines > 0 && bytes.Count(body, []byte("\r\n")) == lines,
+	}
+}
+
+// normalize strips the byte order mark and converts CRLF line endings to LF
+func (e textEncoding) normalize(source []byte) []byte {
+	if e.bom {
+		source = source[len(utf8BOM):]
+	}
+	if e.crlf {
+		source = bytes.ReplaceAll(source, []byte("\r\n"), []byte("\n"))
+	}
+	return source
+}
+
+// restore converts normalized source back to the file's encoding
+func (e textEncoding) restore(source []byte) []byte {
+	if e.crlf {
+		source = bytes.ReplaceAll(source, []byte("\n"), []byte("\r\n"))
+	}
+	if e.bom {
+		source = append(append([]byte{}, utf8BOM...), source...)
+	}
+	return source
+}
+
+// restoreEdits maps edits of the normalized source to byte offsets and line
+// endings of the original file
+func (e textEncoding) restoreEdits(normalized []byte, edits []Edit) []Edit {
+	if !e.bom && !e.crlf {
+		return edits
+	}
+	offset := func(position int) int {
+		restored := position
+		if e.crlf {
+			restored += bytes.Count(normalized[:position], []byte("\n"))
+		}
+		if e.bom {
+			restored += len(utf8BOM)
+		}
+		return restored
+	}
+
+	restored := make([]Edit, 0, len(edits))
+	for _, edit := range edits {
+		replacement := edit.Replacement
+		if e.crlf {
+			replacement = strings.ReplaceAll(replacement, "\n", "\r\n")
+		}
+		restored = append(restored, Edit{
+			Start:       offset(edit.Start),
+			End:         offset(edit.End),
+			Replacement: replacement,
+			Reason:      edit.Reason,
+		})
+	}
+	return restored
+}
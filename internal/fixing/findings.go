@@ -0,0 +1,57 @@
+package fixing
+
+import "github.com/evanrichards/nestjs-module-lint/internal/analysis"
+
+// FindingFix is the fix of a single finding, planned on its own
+type FindingFix struct {
+	File   string
+	Module string
+	RuleID string
+	Name   string
+	// Edits apply to the file as it is on disk
+	Edits []Edit
+}
+
+// PlanFindingFixes computes the fix of each fixable finding on its own, so
+// reports can describe the edits that fix a finding. Findings the fixer
+// skips, or whose fix is rolled back, have no fix.
+func (w *Workflow) PlanFindingFixes(reports []*analysis.ModuleAnalysisResult) ([]FindingFix, error) {
+	var fixes []FindingFix
+	for _, report := range reports {
+		for _, finding := range report.AllFindings() {
+			if _, fixable := fixTypes[finding.RuleID]; !fixable {
+				continue
+			}
+			// A missing import names the injected provider, while its fix
+			// adds the module exporting it
+			selected := finding.Name
+			if finding.RuleID == analysis.RuleMissingImport {
+				if len(finding.Candidates) != 1 {
+					continue
+				}
+				selected = finding.Candidates[0].Name
+			}
+			single := *w
+			single.selector = Selector{
+				Modules: []string{report.ModuleName},
+				Imports: []string{selected},
+				Rules:   []string{finding.RuleID},
+			}
+			plan, err := single.planReports([]*analysis.ModuleAnalysisResult{report}, nil)
+			if err != nil {
+				return nil, err
+			}
+			if len(plan.Files) == 0 {
+				continue
+			}
+			fixes = append(fixes, FindingFix{
+				File:   report.FilePath,
+				Module: report.ModuleName,
+				RuleID: finding.RuleID,
+				Name:   finding.Name,
+				Edits:  plan.Files[0].Edits,
+			})
+		}
+	}
+	return fixes, nil
+}
@@ -0,0 +1,138 @@
+package fixing_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestWorkflow_PlanFindingFixes(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "app.module.ts")
+
+	sourceCode := `import { Module } from "@nestjs/common";
+import { UnusedA } from "./unused-a.module";
+import { UnusedB } from "./unused-b.module";
+
+@Module({
+  imports: [UnusedA, UnusedB],
+})
+export class AppModule {}
+`
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	reports := []*analysis.ModuleAnalysisResult{{
+		ModuleName:    "AppModule",
+		FilePath:      testFile,
+		UnusedImports: []string{"UnusedA", "UnusedB"},
+		Findings: []analysis.Finding{
+			{RuleID: analysis.RuleUnusedQueue, Name: "emails"},
+		},
+	}}
+	workflow := fixing.NewWorkflow(&mockAnalyzer{}, fixing.NewFixer(typescript.GetLanguage()), nil).
+		WithSelector(fixing.Selector{Imports: []string{"UnusedA"}})
+	fixes, err := workflow.PlanFindingFixes(reports)
+	if err != nil {
+		t.Fatalf("PlanFindingFixes failed: %v", err)
+	}
+
+	// Each fixable finding gets its own fix, whatever the selector, and
+	// findings without a fix are left out
+	if len(fixes) != 2 {
+		t.Fatalf("Expected 2 fixes, got %v", fixes)
+	}
+	expected := map[string]string{
+		"UnusedA": `import { Module } from "@nestjs/common";
+import { UnusedB } from "./unused-b.module";
+
+@Module({
+  imports: [UnusedB],
+})
+export class AppModule {}
+`,
+		"UnusedB": `import { Module } from "@nestjs/common";
+import { UnusedA } from "./unused-a.module";
+
+@Module({
+  imports: [UnusedA],
+})
+export class AppModule {}
+`,
+	}
+	for _, fix := range fixes {
+		if fix.File != testFile || fix.Module != "AppModule" || fix.RuleID != analysis.RuleUnusedImport {
+			t.Errorf("Unexpected fix %+v", fix)
+		}
+		fixed, err := fixing.ApplyEdits([]byte(sourceCode), fix.Edits)
+		if err != nil {
+			t.Fatalf("ApplyEdits failed: %v", err)
+		}
+		if string(fixed) != expected[fix.Name] {
+			t.Errorf("Fix of %s mismatch\nGot:\n%s\n\nExpected:\n%s", fix.Name, fixed, expected[fix.Name])
+		}
+	}
+}
+
+// relativeImportPaths imports files by their path relative to the importer
+type relativeImportPaths struct{}
+
+func (relativeImportPaths) ImportSpecifier(fromFile, targetFile string) string {
+	relative, _ := filepath.Rel(filepath.Dir(fromFile), strings.TrimSuffix(targetFile, ".ts"))
+	return "./" + filepath.ToSlash(relative)
+}
+
+func TestWorkflow_PlanFindingFixes_MissingImport(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "orders.module.ts")
+
+	sourceCode := `import { Module } from "@nestjs/common";
+
+@Module({ imports: [] })
+export class OrdersModule {}
+`
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	reports := []*analysis.ModuleAnalysisResult{{
+		ModuleName: "OrdersModule",
+		FilePath:   testFile,
+		Findings: []analysis.Finding{{
+			RuleID: analysis.RuleMissingImport,
+			Name:   "UsersService",
+			Candidates: []analysis.ModuleCandidate{
+				{Name: "UsersModule", FilePath: filepath.Join(tempDir, "users", "users.module.ts")},
+			},
+		}},
+	}}
+	workflow := fixing.NewWorkflow(&mockAnalyzer{}, fixing.NewFixer(typescript.GetLanguage()), relativeImportPaths{})
+	fixes, err := workflow.PlanFindingFixes(reports)
+	if err != nil {
+		t.Fatalf("PlanFindingFixes failed: %v", err)
+	}
+
+	// The fix of the finding about the provider adds the module exporting it
+	if len(fixes) != 1 || fixes[0].RuleID != analysis.RuleMissingImport || fixes[0].Name != "UsersService" {
+		t.Fatalf("Expected a fix of the missing import, got %+v", fixes)
+	}
+	fixed, err := fixing.ApplyEdits([]byte(sourceCode), fixes[0].Edits)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	expected := `import { Module } from "@nestjs/common";
+import { UsersModule } from "./users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}
+`
+	if string(fixed) != expected {
+		t.Errorf("Fix mismatch\nGot:\n%s\n\nExpected:\n%s", fixed, expected)
+	}
+}
@@ -3,232 +3,548 @@ package fixing
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
 // Fixer handles automatic fixing of unused imports
 type Fixer struct {
-	lang *sitter.Language
+	lang             *sitter.Language
+	moduleDecorators []string
 }
 
-// NewFixer creates a new import fixer
-func NewFixer(lang *sitter.Language) *Fixer {
+// NewFixer creates a new import fixer. moduleDecorators lists custom
+// decorators that wrap @Module() metadata.
+func NewFixer(lang *sitter.Language, moduleDecorators ...string) *Fixer {
 	return &Fixer{
-		lang: lang,
+		lang:             lang,
+		moduleDecorators: moduleDecorators,
 	}
 }
 
-// FixUnusedImports removes unused import statements and their references from module imports arrays
+// FixUnusedImports removes unused import statements and their references from
+// the imports arrays of every module in the file
 func (f *Fixer) FixUnusedImports(sourceCode []byte, unusedModules []string) ([]byte, error) {
-	if len(unusedModules) == 0 {
-		return sourceCode, nil
+	return f.FixModule(sourceCode, "", unusedModules, nil)
+}
+
+// RemoveFeatureEntities removes entities from the ORM forFeature() calls in a
+// module's imports, along with import statements no longer referenced
+func (f *Fixer) RemoveFeatureEntities(sourceCode []byte, moduleName string, entities []string) ([]byte, error) {
+	return f.FixModule(sourceCode, moduleName, nil, entities)
+}
+
+// ModuleFix lists what to change in a module. An empty ModuleName applies
+// the fix to every module in the file.
+type ModuleFix struct {
+	ModuleName     string
+	UnusedImports  []string
+	UnusedEntities []string
+	MissingImports []MissingImport
+	// IgnoredImports are kept and marked with an ignore comment
+	IgnoredImports []string
+}
+
+// isEmpty reports whether the fix changes nothing
+func (fix ModuleFix) isEmpty() bool {
+	return len(fix.UnusedImports) == 0 && len(fix.UnusedEntities) == 0 &&
+		len(fix.MissingImports) == 0 && len(fix.IgnoredImports) == 0
+}
+
+// MissingImport is a module to add to a module's imports, along with the
+// module specifier to import it from
+type MissingImport struct {
+	Name      string
+	Specifier string
+}
+
+// FixModule removes unused imports and unused feature entities from a
+// module's metadata, plus the import statements of names that are no longer
+// referenced. An empty moduleName applies the fix to every module in the
+// file.
+func (f *Fixer) FixModule(sourceCode []byte, moduleName string, unusedModules []string, unusedEntities []string) ([]byte, error) {
+	edits, err := f.PlanEdits(sourceCode, []ModuleFix{{
+		ModuleName:     moduleName,
+		UnusedImports:  unusedModules,
+		UnusedEntities: unusedEntities,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return ApplyEdits(sourceCode, edits)
+}
+
+// VerifySyntax checks that a fix does not introduce syntax errors the
+// original source did not have
+func (f *Fixer) VerifySyntax(original, fixed []byte) error {
+	fixedTree, err := sitter.ParseCtx(context.Background(), fixed, f.lang)
+	if err != nil {
+		return fmt.Errorf("failed to parse fixed TypeScript: %w", err)
+	}
+	if !fixedTree.HasError() {
+		return nil
+	}
+	originalTree, err := sitter.ParseCtx(context.Background(), original, f.lang)
+	if err != nil {
+		return fmt.Errorf("failed to parse TypeScript: %w", err)
+	}
+	if !originalTree.HasError() {
+		return fmt.Errorf("fix introduces TypeScript syntax errors")
+	}
+	return nil
+}
+
+// PlanEdits computes the edits that apply the fixes of every module in a
+// file, without changing the source. All edits are computed on the syntax
+// tree of the original source, so bytes outside of the removed nodes stay
+// intact. The returned edits are sorted and do not overlap.
+func (f *Fixer) PlanEdits(sourceCode []byte, fixes []ModuleFix) ([]Edit, error) {
+	edits, _, err := f.PlanChanges(sourceCode, fixes)
+	return edits, err
+}
+
+// PlanChanges computes the edits like PlanEdits, along with a description of
+// the elements and import statements they change
+func (f *Fixer) PlanChanges(sourceCode []byte, fixes []ModuleFix) ([]Edit, Changes, error) {
+	var changes Changes
+	empty := true
+	for _, fix := range fixes {
+		if !fix.isEmpty() {
+			empty = false
+		}
+	}
+	if empty {
+		return nil, changes, nil
 	}
 
 	// Parse the source code to validate it's valid TypeScript
 	tree, err := sitter.ParseCtx(context.Background(), sourceCode, f.lang)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse TypeScript: %w", err)
+		return nil, changes, fmt.Errorf("failed to parse TypeScript: %w", err)
 	}
 
 	// Check for syntax errors in the parsed tree
 	if tree.HasError() {
-		return nil, fmt.Errorf("TypeScript syntax error detected")
+		return nil, changes, fmt.Errorf("TypeScript syntax error detected")
 	}
 
-	source := string(sourceCode)
-
-	// Check if there's a blank line after imports before we start modifying
-	hasBlankLineAfterImports := f.hasBlankLineAfterImports(source)
-
-	// Remove unused import statements
-	for _, moduleName := range unusedModules {
-		source = f.removeImportStatement(source, moduleName)
+	declarations, err := parser.ParseModuleDeclarations(tree, sourceCode, f.moduleDecorators...)
+	if err != nil {
+		return nil, changes, err
 	}
 
-	// Remove unused modules from @Module imports arrays
-	source = f.removeFromModuleImports(source, unusedModules)
-
-	// If there was a blank line after imports originally, ensure it's preserved
-	if hasBlankLineAfterImports {
-		source = f.ensureBlankLineAfterImports(source)
+	var elements []*sitter.Node
+	var reasons []string
+	var removedNames []string
+	for _, fix := range fixes {
+		removeModules := toSet(fix.UnusedImports)
+		removeEntities := toSet(fix.UnusedEntities)
+		for _, declaration := range declarations {
+			if fix.ModuleName != "" && declaration.Name != fix.ModuleName {
+				continue
+			}
+			for _, element := range declaration.ArrayElements("imports") {
+				name := element.Content(sourceCode)
+				// Elements of const metadata may be shared with other modules
+				if !removeModules[name] || !declaration.InDecorator(element) {
+					continue
+				}
+				elements = append(elements, element)
+				reasons = append(reasons, fmt.Sprintf("remove unused import %s from %s", name, declaration.Name))
+				removedNames = append(removedNames, name)
+				changes.RemovedElements = append(changes.RemovedElements, ElementChange{Module: declaration.Name, Name: name, Kind: ElementImport})
+			}
+			for _, registration := range declaration.FeatureRegistrations() {
+				if !removeEntities[registration.Name] || !declaration.InDecorator(registration.Node) {
+					continue
+				}
+				elements = append(elements, registration.Node)
+				reasons = append(reasons, fmt.Sprintf("remove unused feature entity %s from %s", registration.Name, declaration.Name))
+				removedNames = append(removedNames, identifiersIn(registration.Node, sourceCode)...)
+				changes.RemovedElements = append(changes.RemovedElements, ElementChange{Module: declaration.Name, Name: registration.Name, Kind: ElementFeatureEntity})
+			}
+		}
 	}
 
-	// Clean up excessive blank lines (but preserve intended single blank lines)
-	source = f.cleanupBlankLines(source)
-
-	return []byte(source), nil
-}
-
-// removeImportStatement removes import statements for the given module
-func (f *Fixer) removeImportStatement(source, moduleName string) string {
-	// Pattern to match import statements
-	// Handles: import { ModuleName } from '...';
-	//         import ModuleName from '...';
-	//         import { ModuleName as Alias } from '...';
-	patterns := []string{
-		// Named import: import { ModuleName } from '...'; [optional comment]
-		fmt.Sprintf(`import\s*{\s*%s\s*}\s*from\s*['"][^'"]*['"];[^\n]*\n?`, regexp.QuoteMeta(moduleName)),
-		// Default import: import ModuleName from '...'; [optional comment]
-		fmt.Sprintf(`import\s+%s\s+from\s*['"][^'"]*['"];[^\n]*\n?`, regexp.QuoteMeta(moduleName)),
-		// Named import with alias: import { ModuleName as Alias } from '...'; [optional comment]
-		fmt.Sprintf(`import\s*{\s*[^}]*%s[^}]*}\s*from\s*['"][^'"]*['"];[^\n]*\n?`, regexp.QuoteMeta(moduleName)),
+	removed := make(map[uint32]bool)
+	for _, element := range elements {
+		removed[element.StartByte()] = true
+	}
+	var edits []Edit
+	for i, element := range elements {
+		edit := elementEdit(element, sourceCode, removed)
+		edit.Reason = reasons[i]
+		edits = append(edits, edit)
 	}
 
-	for _, pattern := range patterns {
-		regex := regexp.MustCompile(pattern)
-		if regex.MatchString(source) {
-			source = regex.ReplaceAllString(source, "")
-			break // Remove only the first match to avoid over-removal
+	// Remove import statements whose bindings nothing references any more
+	statementEdits, statementChanges := f.importEdits(tree, sourceCode, removedNames, edits)
+	edits = append(edits, statementEdits...)
+	changes.ImportStatements = append(changes.ImportStatements, statementChanges...)
+
+	// Mark the ignored imports with a disable-line comment
+	for _, fix := range fixes {
+		ignore := toSet(fix.IgnoredImports)
+		for _, declaration := range declarations {
+			if len(ignore) == 0 || (fix.ModuleName != "" && declaration.Name != fix.ModuleName) {
+				continue
+			}
+			for _, element := range declaration.ArrayElements("imports") {
+				name := element.Content(sourceCode)
+				if !ignore[name] || !declaration.InDecorator(element) {
+					continue
+				}
+				edit := ignoreCommentEdit(element, sourceCode)
+				edit.Reason = fmt.Sprintf("ignore unused import %s in %s", name, declaration.Name)
+				edits = append(edits, edit)
+				changes.IgnoredElements = append(changes.IgnoredElements, ElementChange{Module: declaration.Name, Name: name, Kind: ElementImport})
+			}
 		}
 	}
 
-	return source
-}
-
-// removeFromModuleImports removes modules from @Module imports arrays
-func (f *Fixer) removeFromModuleImports(source string, unusedModules []string) string {
-	// Find @Module decorator with imports array
-	modulePattern := regexp.MustCompile(`@Module\s*\(\s*{[^}]*imports\s*:\s*\[([^\]]*)\][^}]*}\s*\)`)
-
-	return modulePattern.ReplaceAllStringFunc(source, func(moduleMatch string) string {
-		// Extract the imports array content
-		importsPattern := regexp.MustCompile(`imports\s*:\s*\[([^\]]*)\]`)
-		importsMatch := importsPattern.FindStringSubmatch(moduleMatch)
-
-		if len(importsMatch) < 2 {
-			return moduleMatch // No imports array found, return unchanged
+	// Add missing imports to the modules and their import statements
+	var added []MissingImport
+	for _, fix := range fixes {
+		if len(fix.MissingImports) == 0 {
+			continue
 		}
+		for _, declaration := range declarations {
+			if fix.ModuleName != "" && declaration.Name != fix.ModuleName {
+				continue
+			}
+			edit, inserted := insertModuleImports(declaration, fix.MissingImports, sourceCode, removed)
+			if len(inserted) == 0 {
+				continue
+			}
+			edits = append(edits, edit)
+			added = append(added, inserted...)
+			for _, module := range inserted {
+				changes.AddedElements = append(changes.AddedElements, ElementChange{Module: declaration.Name, Name: module.Name, Kind: ElementImport})
+			}
+		}
+	}
+	insertions, insertionChanges := importStatementInsertions(tree, sourceCode, added)
+	edits = append(edits, insertions...)
+	changes.ImportStatements = append(changes.ImportStatements, insertionChanges...)
 
-		arrayContent := importsMatch[1]
-		updatedArray := f.removeModulesFromArray(arrayContent, unusedModules)
-
-		// Replace the imports array with the updated version
-		return importsPattern.ReplaceAllString(moduleMatch, fmt.Sprintf("imports: [%s]", updatedArray))
-	})
+	merged, err := MergeEdits(edits, len(sourceCode))
+	if err != nil {
+		return nil, changes, err
+	}
+	return merged, changes, nil
 }
 
-// removeModulesFromArray removes specified modules from an array string
-func (f *Fixer) removeModulesFromArray(arrayContent string, modulesToRemove []string) string {
-	// Create a set of modules to remove for efficient lookup
-	removeSet := make(map[string]bool)
-	for _, module := range modulesToRemove {
-		removeSet[module] = true
+// importEdits removes the import bindings of names that are referenced
+// nowhere outside of import statements and the edited ranges. Only the unused
+// specifiers are removed; a statement is dropped once none of its bindings
+// remain. Side-effect imports such as import './polyfills' bind nothing and
+// are never touched.
+func (f *Fixer) importEdits(root *sitter.Node, sourceCode []byte, names []string, removed []Edit) ([]Edit, []ImportChange) {
+	unused := make(map[string]bool)
+	for _, name := range names {
+		if !unused[name] && !isReferenced(root, sourceCode, name, removed) {
+			unused[name] = true
+		}
+	}
+	if len(unused) == 0 {
+		return nil, nil
 	}
 
-	// Check if it's a single-line or multi-line array
-	if strings.Contains(arrayContent, "\n") {
-		return f.removeFromMultilineArray(arrayContent, removeSet)
-	} else {
-		return f.removeFromInlineArray(arrayContent, removeSet)
+	var edits []Edit
+	var changes []ImportChange
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		statement := root.NamedChild(i)
+		if statement.Type() != "import_statement" {
+			continue
+		}
+		bindings := importBindings(statement, sourceCode)
+		var removedBindings []string
+		for _, binding := range bindings {
+			if unused[binding.name] {
+				removedBindings = append(removedBindings, binding.name)
+			}
+		}
+		if len(removedBindings) == 0 {
+			continue
+		}
+		specifier := ""
+		if source := statement.ChildByFieldName("source"); source != nil {
+			specifier = stringContent(source, sourceCode)
+		}
+		if len(removedBindings) == len(bindings) {
+			edit := statementEdit(statement, sourceCode)
+			edit.Reason = fmt.Sprintf("remove unused import of %s", strings.Join(removedBindings, ", "))
+			edits = append(edits, edit)
+			changes = append(changes, ImportChange{Action: ImportRemoved, Specifier: specifier, Removed: removedBindings})
+			continue
+		}
+		edits = append(edits, bindingEdits(bindings, unused, sourceCode)...)
+		changes = append(changes, ImportChange{Action: ImportModified, Specifier: specifier, Removed: removedBindings})
 	}
+	return edits, changes
 }
 
-// removeFromInlineArray handles single-line arrays like: [A, B, C]
-func (f *Fixer) removeFromInlineArray(arrayContent string, removeSet map[string]bool) string {
-	// Split by comma and filter
-	parts := strings.Split(arrayContent, ",")
-	var kept []string
+// importBinding is a local name bound by an import statement. node is the
+// default import identifier, the namespace import or the import specifier,
+// and group is the named_imports node holding a specifier.
+type importBinding struct {
+	name  string
+	node  *sitter.Node
+	group *sitter.Node
+}
 
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed == "" {
+// importBindings returns the local names an import statement binds
+func importBindings(statement *sitter.Node, sourceCode []byte) []importBinding {
+	var bindings []importBinding
+	for i := 0; i < int(statement.NamedChildCount()); i++ {
+		clause := statement.NamedChild(i)
+		if clause.Type() != "import_clause" {
 			continue
 		}
+		for j := 0; j < int(clause.NamedChildCount()); j++ {
+			child := clause.NamedChild(j)
+			switch child.Type() {
+			case "identifier":
+				bindings = append(bindings, importBinding{name: child.Content(sourceCode), node: child})
+			case "namespace_import":
+				if child.NamedChildCount() > 0 {
+					bindings = append(bindings, importBinding{name: child.NamedChild(0).Content(sourceCode), node: child})
+				}
+			case "named_imports":
+				for k := 0; k < int(child.NamedChildCount()); k++ {
+					specifier := child.NamedChild(k)
+					if specifier.Type() != "import_specifier" {
+						continue
+					}
+					local := specifier.ChildByFieldName("alias")
+					if local == nil {
+						local = specifier.ChildByFieldName("name")
+					}
+					if local != nil {
+						bindings = append(bindings, importBinding{name: local.Content(sourceCode), node: specifier, group: child})
+					}
+				}
+			}
+		}
+	}
+	return bindings
+}
 
-		if !removeSet[trimmed] {
-			kept = append(kept, trimmed) // Use trimmed to normalize spacing
+// bindingEdits removes the unused bindings of a statement that keeps at least
+// one binding. A named import group that loses every specifier is removed
+// with its braces, e.g. import Default, { Unused } from '...' keeps Default.
+func bindingEdits(bindings []importBinding, unused map[string]bool, sourceCode []byte) []Edit {
+	groupSize := make(map[uint32]int)
+	groupRemoved := make(map[uint32]int)
+	for _, binding := range bindings {
+		if binding.group == nil {
+			continue
+		}
+		groupSize[binding.group.StartByte()]++
+		if unused[binding.name] {
+			groupRemoved[binding.group.StartByte()]++
 		}
 	}
 
-	if len(kept) == 0 {
-		return "" // Empty array
+	// Removed nodes, keyed by start byte, so separators are handled across
+	// adjacent removals
+	var nodes []*sitter.Node
+	removed := make(map[uint32]bool)
+	groups := make(map[uint32]bool)
+	for _, binding := range bindings {
+		if !unused[binding.name] {
+			continue
+		}
+		node := binding.node
+		if binding.group != nil && groupRemoved[binding.group.StartByte()] == groupSize[binding.group.StartByte()] {
+			if groups[binding.group.StartByte()] {
+				continue
+			}
+			groups[binding.group.StartByte()] = true
+			node = binding.group
+		}
+		nodes = append(nodes, node)
+		removed[node.StartByte()] = true
 	}
 
-	// Join with proper spacing: comma followed by space
-	return strings.Join(kept, ", ")
+	var edits []Edit
+	for _, node := range nodes {
+		edit := elementEdit(node, sourceCode, removed)
+		edit.Reason = fmt.Sprintf("remove unused import binding %s", node.Content(sourceCode))
+		edits = append(edits, edit)
+	}
+	return edits
 }
 
-// removeFromMultilineArray handles multi-line arrays with proper formatting
-func (f *Fixer) removeFromMultilineArray(arrayContent string, removeSet map[string]bool) string {
-	lines := strings.Split(arrayContent, "\n")
-	var kept []string
+// elementEdit returns the edit that removes an array element together with
+// its separator and attached comments. An element on its own line is removed
+// with the whole line and any comment lines directly above it. removed holds
+// the start bytes of every element being removed, so that removing the last
+// elements of an inline array also removes the separator before them.
+func elementEdit(node *sitter.Node, sourceCode []byte, removed map[uint32]bool) Edit {
+	start, end := int(node.StartByte()), int(node.EndByte())
+
+	next := node.NextSibling()
+	hasComma := next != nil && next.Type() == ","
+	if hasComma {
+		end = int(next.EndByte())
+		next = next.NextSibling()
+	}
+	// A comment trailing the element on the same line belongs to it
+	if next != nil && next.Type() == "comment" && next.StartPoint().Row == node.EndPoint().Row {
+		end = int(next.EndByte())
+	}
 
-	for _, line := range lines {
-		moduleName := f.extractModuleName(line)
-		if moduleName == "" || !removeSet[moduleName] {
-			kept = append(kept, line)
+	if lineStart, lineEnd, ok := ownLine(sourceCode, start, end); ok {
+		// Comment lines directly above the element belong to it
+		for previous := node.PrevSibling(); previous != nil && previous.Type() == "comment"; previous = previous.PrevSibling() {
+			commentStart, _, ok := ownLine(sourceCode, int(previous.StartByte()), int(previous.EndByte()))
+			if !ok || int(previous.EndPoint().Row)+1 != int(rowAt(sourceCode, lineStart)) {
+				break
+			}
+			lineStart = commentStart
 		}
+		return Edit{Start: lineStart, End: lineEnd}
 	}
 
-	return strings.Join(kept, "\n")
-}
-
-// extractModuleName extracts the module name from a line in the imports array
-func (f *Fixer) extractModuleName(line string) string {
-	// Remove whitespace and trailing comma
-	trimmed := strings.TrimSpace(line)
-	trimmed = strings.TrimSuffix(trimmed, ",")
-	trimmed = strings.TrimSpace(trimmed)
+	if hasComma {
+		// Inline element followed by others: remove "X, "
+		for end < len(sourceCode) && isHorizontalSpace(sourceCode[end]) {
+			end++
+		}
+		return Edit{Start: start, End: end}
+	}
 
-	// Skip empty lines or comment-only lines
-	if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") {
-		return ""
+	// Last inline element: remove the separator after the last element that
+	// is kept instead
+	for previous := node.PrevSibling(); previous != nil; previous = previous.PrevSibling() {
+		if !previous.IsNamed() || previous.Type() == "comment" || removed[previous.StartByte()] {
+			continue
+		}
+		if separator := previous.NextSibling(); separator != nil && separator.Type() == "," {
+			start = int(separator.StartByte())
+		}
+		break
 	}
+	return Edit{Start: start, End: end}
+}
 
-	// Remove inline comments (e.g., "ModuleName // comment" -> "ModuleName")
-	if commentIndex := strings.Index(trimmed, "//"); commentIndex != -1 {
-		trimmed = strings.TrimSpace(trimmed[:commentIndex])
+// statementEdit returns the edit that removes a statement, with its whole
+// line when nothing but a trailing comment shares it
+func statementEdit(node *sitter.Node, sourceCode []byte) Edit {
+	start, end := int(node.StartByte()), int(node.EndByte())
+	if next := node.NextSibling(); next != nil && next.Type() == "comment" && next.StartPoint().Row == node.EndPoint().Row {
+		end = int(next.EndByte())
 	}
-	if commentIndex := strings.Index(trimmed, "/*"); commentIndex != -1 {
-		trimmed = strings.TrimSpace(trimmed[:commentIndex])
+	if lineStart, lineEnd, ok := ownLine(sourceCode, start, end); ok {
+		return Edit{Start: lineStart, End: lineEnd}
 	}
-
-	// Remove trailing comma again (in case it was after the module name but before the comment)
-	trimmed = strings.TrimSuffix(trimmed, ",")
-	trimmed = strings.TrimSpace(trimmed)
-
-	return trimmed
+	for end < len(sourceCode) && isHorizontalSpace(sourceCode[end]) {
+		end++
+	}
+	return Edit{Start: start, End: end}
 }
 
-// hasBlankLineAfterImports checks if there's a blank line between imports and @Module
-func (f *Fixer) hasBlankLineAfterImports(source string) bool {
-	// Look for the pattern: import statements followed by blank line(s) followed by @Module
-	pattern := regexp.MustCompile(`import\s+[^;]+;.*?\n\s*\n\s*@Module`)
-	return pattern.MatchString(source)
+// ownLine reports whether [start, end) is alone on its lines apart from
+// indentation, and returns the range extended to the full lines including the
+// line break
+func ownLine(sourceCode []byte, start, end int) (int, int, bool) {
+	lineStart := start
+	for lineStart > 0 && isHorizontalSpace(sourceCode[lineStart-1]) {
+		lineStart--
+	}
+	lineEnd := end
+	for lineEnd < len(sourceCode) && isHorizontalSpace(sourceCode[lineEnd]) {
+		lineEnd++
+	}
+	if lineStart > 0 && sourceCode[lineStart-1] != '\n' {
+		return start, end, false
+	}
+	if lineEnd < len(sourceCode) && sourceCode[lineEnd] == '\r' {
+		lineEnd++
+	}
+	if lineEnd < len(sourceCode) {
+		if sourceCode[lineEnd] != '\n' {
+			return start, end, false
+		}
+		lineEnd++
+	}
+	return lineStart, lineEnd, true
 }
 
-// ensureBlankLineAfterImports ensures there's a blank line between imports and @Module
-func (f *Fixer) ensureBlankLineAfterImports(source string) string {
-	// Pattern to match: (last import)(possible whitespace/newlines)(@Module)
-	pattern := regexp.MustCompile(`(import\s+[^;]+;\s*\n)(\s*)(@Module)`)
-
-	return pattern.ReplaceAllStringFunc(source, func(match string) string {
-		// Extract the components
-		submatches := pattern.FindStringSubmatch(match)
-		if len(submatches) != 4 {
-			return match
+// rowAt returns the zero based line number of a byte offset
+func rowAt(sourceCode []byte, offset int) int {
+	row := 0
+	for _, b := range sourceCode[:offset] {
+		if b == '\n' {
+			row++
 		}
+	}
+	return row
+}
 
-		lastImport := submatches[1]
-		whitespace := submatches[2]
-		moduleDecorator := submatches[3]
+// isReferenced checks if a name is used anywhere outside of import statements
+// and the ranges being removed
+func isReferenced(root *sitter.Node, sourceCode []byte, name string, removed []Edit) bool {
+	referenced := false
+	walk(root, func(n *sitter.Node) bool {
+		if referenced || n.Type() == "import_statement" || inEdits(n, removed) {
+			return false
+		}
+		switch n.Type() {
+		case "identifier", "type_identifier", "shorthand_property_identifier":
+			if n.Content(sourceCode) == name {
+				referenced = true
+			}
+		}
+		return true
+	})
+	return referenced
+}
 
-		// If there's no blank line (just whitespace, no empty lines), add one
-		if !strings.Contains(whitespace, "\n\n") && !strings.Contains(whitespace, "\n\r\n") && !strings.Contains(whitespace, "\r\n\r\n") {
-			return lastImport + "\n" + moduleDecorator
+// inEdits reports whether a node lies entirely within one of the edits
+func inEdits(n *sitter.Node, edits []Edit) bool {
+	for _, edit := range edits {
+		if int(n.StartByte()) >= edit.Start && int(n.EndByte()) <= edit.End {
+			return true
 		}
+	}
+	return false
+}
 
-		return match
+// identifiersIn returns the names of the identifiers within a node
+func identifiersIn(node *sitter.Node, sourceCode []byte) []string {
+	var names []string
+	walk(node, func(n *sitter.Node) bool {
+		if n.Type() == "identifier" {
+			names = append(names, n.Content(sourceCode))
+		}
+		return true
 	})
+	return names
+}
+
+// walk visits a node and its named descendants depth-first, descending only
+// while visit returns true
+func walk(node *sitter.Node, visit func(*sitter.Node) bool) {
+	if !visit(node) {
+		return
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		walk(node.NamedChild(i), visit)
+	}
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
 }
 
-// cleanupBlankLines removes excessive blank lines from the source while preserving single blank lines
-func (f *Fixer) cleanupBlankLines(source string) string {
-	// Replace 3 or more consecutive blank lines with exactly 2 blank lines
-	// This preserves single blank lines (which are often intentional formatting)
-	excessiveBlankLines := regexp.MustCompile(`(\n\s*){3,}`)
-	return excessiveBlankLines.ReplaceAllString(source, "\n\n")
+func isHorizontalSpace(b byte) bool {
+	return b == ' ' || b == '\t'
 }
@@ -1,6 +1,7 @@
 package fixing_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -85,6 +86,28 @@ import { UsedModule } from "./used.module";
   ],
   providers: [],
 })
+export class AppModule {}`,
+		},
+		{
+			name: "keep import statement when the module is still referenced",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+
+const metadata = {
+  imports: [UnusedModule],
+};
+
+@Module(metadata)
+export class AppModule {}`,
+			unusedModules: []string{"UnusedModule"},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+
+const metadata = {
+  imports: [UnusedModule],
+};
+
+@Module(metadata)
 export class AppModule {}`,
 		},
 		{
@@ -114,6 +137,286 @@ export class AppModule {}`,
 	}
 }
 
+func TestFixer_FixModule(t *testing.T) {
+	lang := typescript.GetLanguage()
+	fixer := fixing.NewFixer(lang)
+
+	tests := []struct {
+		name           string
+		sourceCode     string
+		moduleName     string
+		unusedModules  []string
+		expectedResult string
+	}{
+		{
+			name: "decorator object with nested braces",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+import { ConfigModule } from "@nestjs/config";
+
+
+@Module({
+  imports: [ConfigModule.forRoot({ isGlobal: true }), UnusedModule],
+  providers: [{ provide: "CONFIG", useFactory: () => ({ debug: true }) }],
+})
+export class AppModule {}`,
+			moduleName:    "AppModule",
+			unusedModules: []string{"UnusedModule"},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "@nestjs/config";
+
+
+@Module({
+  imports: [ConfigModule.forRoot({ isGlobal: true })],
+  providers: [{ provide: "CONFIG", useFactory: () => ({ debug: true }) }],
+})
+export class AppModule {}`,
+		},
+		{
+			name: "only the named module is edited",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { SharedModule } from "./shared.module";
+
+@Module({
+  imports: [SharedModule],
+})
+export class FirstModule {}
+
+@Module({
+  imports: [SharedModule],
+})
+export class SecondModule {}`,
+			moduleName:    "SecondModule",
+			unusedModules: []string{"SharedModule"},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { SharedModule } from "./shared.module";
+
+@Module({
+  imports: [SharedModule],
+})
+export class FirstModule {}
+
+@Module({
+  imports: [],
+})
+export class SecondModule {}`,
+		},
+		{
+			name: "remove comments attached to the element",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+import { UsedModule } from "./used.module";
+
+@Module({
+  imports: [
+    // Legacy module, kept for the migration
+    UnusedModule, // remove after v2
+    /* still needed */
+    UsedModule,
+  ],
+})
+export class AppModule {}`,
+			moduleName:    "AppModule",
+			unusedModules: []string{"UnusedModule"},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UsedModule } from "./used.module";
+
+@Module({
+  imports: [
+    /* still needed */
+    UsedModule,
+  ],
+})
+export class AppModule {}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fixer.FixModule([]byte(tt.sourceCode), tt.moduleName, tt.unusedModules, nil)
+			if err != nil {
+				t.Fatalf("FixModule failed: %v", err)
+			}
+
+			if string(result) != tt.expectedResult {
+				t.Errorf("Result mismatch\nGot:\n%s\n\nExpected:\n%s", result, tt.expectedResult)
+			}
+		})
+	}
+}
+
+func TestFixer_FixUnusedImports_Specifiers(t *testing.T) {
+	lang := typescript.GetLanguage()
+	fixer := fixing.NewFixer(lang)
+
+	module := `
+@Module({
+  imports: [%s],
+  providers: [UsersService],
+})
+export class AppModule {}`
+
+	tests := []struct {
+		name          string
+		imports       string
+		array         string
+		unusedModules []string
+		expected      string
+		expectedArray string
+	}{
+		{
+			name:          "keep the other specifiers of the statement",
+			imports:       `import { UsersModule, UsersService } from "./users";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import { UsersService } from "./users";`,
+		},
+		{
+			name:          "remove the last specifier",
+			imports:       `import { UsersService, UsersModule } from "./users";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import { UsersService } from "./users";`,
+		},
+		{
+			name:          "keep the default import",
+			imports:       `import UsersService, { UsersModule } from "./users";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import UsersService from "./users";`,
+		},
+		{
+			name:          "keep the named imports when the default is removed",
+			imports:       `import UsersModule, { UsersService } from "./users";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import { UsersService } from "./users";`,
+		},
+		{
+			name:          "remove an aliased specifier",
+			imports:       `import { Base as UsersModule, UsersService } from "./users";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import { UsersService } from "./users";`,
+		},
+		{
+			name: "do not match names by substring",
+			imports: `import { OAuthModuleOptions } from "./oauth";
+import { AuthModule } from "./auth";
+import { UsersService } from "./users";`,
+			array:         "AuthModule",
+			unusedModules: []string{"AuthModule"},
+			expected: `import { OAuthModuleOptions } from "./oauth";
+import { UsersService } from "./users";`,
+		},
+		{
+			name: "drop the statement once it is empty",
+			imports: `import { UsersModule } from "./users";
+import { UsersService } from "./users.service";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import { UsersService } from "./users.service";`,
+		},
+		{
+			name: "leave side-effect imports alone",
+			imports: `import "./polyfills";
+import { UsersModule, UsersService } from "./users";`,
+			array:         "UsersModule",
+			unusedModules: []string{"UsersModule"},
+			expected: `import "./polyfills";
+import { UsersService } from "./users";`,
+		},
+		{
+			name:          "keep the binding while it is still referenced",
+			imports:       `import { UsersModule, UsersService } from "./users";`,
+			array:         "UsersModule, forwardRef(() => UsersModule)",
+			unusedModules: []string{"UsersModule"},
+			expected:      `import { UsersModule, UsersService } from "./users";`,
+			expectedArray: "forwardRef(() => UsersModule)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.imports + "\n" + fmt.Sprintf(module, tt.array)
+			expected := tt.expected + "\n" + fmt.Sprintf(module, tt.expectedArray)
+
+			result, err := fixer.FixUnusedImports([]byte(source), tt.unusedModules)
+			if err != nil {
+				t.Fatalf("FixUnusedImports failed: %v", err)
+			}
+
+			if string(result) != expected {
+				t.Errorf("Result mismatch\nGot:\n%s\n\nExpected:\n%s", result, expected)
+			}
+		})
+	}
+}
+
+func TestFixer_PlanEdits(t *testing.T) {
+	lang := typescript.GetLanguage()
+	fixer := fixing.NewFixer(lang)
+
+	// Both modules drop SharedModule, so its import is removed as well
+	sourceCode := `import { Module } from "@nestjs/common";
+import { SharedModule } from "./shared.module";
+
+@Module({ imports: [SharedModule] })
+export class FirstModule {}
+
+@Module({ imports: [SharedModule] })
+export class SecondModule {}
+`
+
+	edits, err := fixer.PlanEdits([]byte(sourceCode), []fixing.ModuleFix{
+		{ModuleName: "FirstModule", UnusedImports: []string{"SharedModule"}},
+		{ModuleName: "SecondModule", UnusedImports: []string{"SharedModule"}},
+	})
+	if err != nil {
+		t.Fatalf("PlanEdits failed: %v", err)
+	}
+
+	expectedReasons := []string{
+		"remove unused import of SharedModule",
+		"remove unused import SharedModule from FirstModule",
+		"remove unused import SharedModule from SecondModule",
+	}
+	if len(edits) != len(expectedReasons) {
+		t.Fatalf("Expected %d edits, got %v", len(expectedReasons), edits)
+	}
+	for i, edit := range edits {
+		if edit.Reason != expectedReasons[i] {
+			t.Errorf("Expected edit %d reason %q, got %q", i, expectedReasons[i], edit.Reason)
+		}
+		if i > 0 && edit.Start < edits[i-1].End {
+			t.Errorf("Expected edits to be sorted and not to overlap, got %v", edits)
+		}
+	}
+}
+
+func TestApplyEdits(t *testing.T) {
+	source := []byte("[A, B, C]")
+
+	result, err := fixing.ApplyEdits(source, []fixing.Edit{
+		{Start: 4, End: 7},
+		{Start: 1, End: 5},
+	})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(result) != "[C]" {
+		t.Errorf("Expected overlapping deletions to merge, got %q", result)
+	}
+
+	_, err = fixing.ApplyEdits(source, []fixing.Edit{
+		{Start: 1, End: 5, Replacement: "X"},
+		{Start: 4, End: 7},
+	})
+	if err == nil {
+		t.Error("Expected error for overlapping replacement")
+	}
+}
+
 func TestFixer_FixUnusedImports_InvalidSyntax(t *testing.T) {
 	lang := typescript.GetLanguage()
 	fixer := fixing.NewFixer(lang)
@@ -126,3 +429,341 @@ func TestFixer_FixUnusedImports_InvalidSyntax(t *testing.T) {
 		t.Error("Expected error for invalid TypeScript syntax")
 	}
 }
+
+func TestFixer_RemoveFeatureEntities(t *testing.T) {
+	lang := typescript.GetLanguage()
+	fixer := fixing.NewFixer(lang)
+
+	tests := []struct {
+		name           string
+		sourceCode     string
+		moduleName     string
+		entities       []string
+		expectedResult string
+	}{
+		{
+			name: "remove inline entity",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { User } from "./user.entity";
+import { Order } from "./order.entity";
+
+@Module({
+  imports: [TypeOrmModule.forFeature([User, Order])],
+})
+export class UsersModule {}`,
+			moduleName: "UsersModule",
+			entities:   []string{"Order"},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { User } from "./user.entity";
+
+@Module({
+  imports: [TypeOrmModule.forFeature([User])],
+})
+export class UsersModule {}`,
+		},
+		{
+			name: "remove mongoose model and its schema import",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { Cat, CatSchema } from "./cat.schema";
+import { Dog, DogSchema } from "./dog.schema";
+
+@Module({
+  imports: [
+    MongooseModule.forFeature([
+      { name: Cat.name, schema: CatSchema },
+      { name: Dog.name, schema: DogSchema },
+    ]),
+  ],
+})
+export class PetsModule {}`,
+			moduleName: "PetsModule",
+			entities:   []string{"Dog"},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { Cat, CatSchema } from "./cat.schema";
+
+@Module({
+  imports: [
+    MongooseModule.forFeature([
+      { name: Cat.name, schema: CatSchema },
+    ]),
+  ],
+})
+export class PetsModule {}`,
+		},
+		{
+			name: "remove every entity",
+			sourceCode: `import { Module } from "@nestjs/common";
+
+@Module({
+  imports: [TypeOrmModule.forFeature([User, Order])],
+})
+export class UsersModule {}`,
+			moduleName: "UsersModule",
+			entities:   []string{"User", "Order"},
+			expectedResult: `import { Module } from "@nestjs/common";
+
+@Module({
+  imports: [TypeOrmModule.forFeature([])],
+})
+export class UsersModule {}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fixer.RemoveFeatureEntities([]byte(tt.sourceCode), tt.moduleName, tt.entities)
+			if err != nil {
+				t.Fatalf("RemoveFeatureEntities failed: %v", err)
+			}
+
+			if strings.TrimSpace(string(result)) != strings.TrimSpace(tt.expectedResult) {
+				t.Errorf("Result mismatch\nGot:\n%s\n\nExpected:\n%s", result, tt.expectedResult)
+			}
+		})
+	}
+}
+
+func TestFixer_PlanEdits_MissingImports(t *testing.T) {
+	lang := typescript.GetLanguage()
+	fixer := fixing.NewFixer(lang)
+
+	usersModule := fixing.MissingImport{Name: "UsersModule", Specifier: "@users/users.module"}
+
+	tests := []struct {
+		name           string
+		sourceCode     string
+		unused         []string
+		missing        []fixing.MissingImport
+		expectedResult string
+	}{
+		{
+			name: "replace only inline element",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+
+@Module({ imports: [UnusedModule] })
+export class OrdersModule {}`,
+			unused:  []string{"UnusedModule"},
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UsersModule } from "@users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "insert before removed trailing inline element",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "./config.module";
+import { UnusedModule } from "./unused.module";
+
+@Module({ imports: [ConfigModule, UnusedModule] })
+export class OrdersModule {}`,
+			unused:  []string{"UnusedModule"},
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "./config.module";
+import { UsersModule } from "@users/users.module";
+
+@Module({ imports: [ConfigModule, UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "replace every multiline element",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+
+@Module({
+  imports: [
+    UnusedModule,
+  ],
+})
+export class OrdersModule {}`,
+			unused:  []string{"UnusedModule"},
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UsersModule } from "@users/users.module";
+
+@Module({
+  imports: [
+    UsersModule,
+  ],
+})
+export class OrdersModule {}`,
+		},
+		{
+			name: "add imports property",
+			sourceCode: `import { Module } from '@nestjs/common';
+import { OrdersService } from './orders.service';
+
+@Module({
+  providers: [OrdersService],
+})
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from '@nestjs/common';
+import { OrdersService } from './orders.service';
+import { UsersModule } from '@users/users.module';
+
+@Module({
+  imports: [UsersModule],
+  providers: [OrdersService],
+})
+export class OrdersModule {}`,
+		},
+		{
+			name: "insert into empty array",
+			sourceCode: `import { Module } from "@nestjs/common";
+
+@Module({ imports: [] })
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UsersModule } from "@users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "insert into inline array",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "./config.module";
+
+@Module({ imports: [ConfigModule] })
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "./config.module";
+import { UsersModule } from "@users/users.module";
+
+@Module({ imports: [ConfigModule, UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "insert into multiline array",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "./config.module";
+
+@Module({
+  imports: [
+    ConfigModule,
+  ],
+})
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { ConfigModule } from "./config.module";
+import { UsersModule } from "@users/users.module";
+
+@Module({
+  imports: [
+    ConfigModule,
+    UsersModule,
+  ],
+})
+export class OrdersModule {}`,
+		},
+		{
+			name: "merge into existing import statement",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { UsersService } from "@users/users.module";
+
+@Module({ imports: [] })
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UsersService, UsersModule } from "@users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "add value import beside type-only import statement",
+			sourceCode: `import { Module } from "@nestjs/common";
+import type { UsersOptions } from "@users/users.module";
+
+@Module({ imports: [] })
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import type { UsersOptions } from "@users/users.module";
+import { UsersModule } from "@users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "merge into value import after type-only import statement",
+			sourceCode: `import { Module } from "@nestjs/common";
+import type { UsersOptions } from "@users/users.module";
+import { UsersService } from "@users/users.module";
+
+@Module({ imports: [] })
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import type { UsersOptions } from "@users/users.module";
+import { UsersService, UsersModule } from "@users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}`,
+		},
+		{
+			name: "drop type modifier of type-only specifier",
+			sourceCode: `import { Module } from "@nestjs/common";
+import { type UsersModule, UsersService } from "@users/users.module";
+
+@Module({ imports: [] })
+export class OrdersModule {}`,
+			missing: []fixing.MissingImport{usersModule},
+			expectedResult: `import { Module } from "@nestjs/common";
+import { UsersModule, UsersService } from "@users/users.module";
+
+@Module({ imports: [UsersModule] })
+export class OrdersModule {}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits, err := fixer.PlanEdits([]byte(tt.sourceCode), []fixing.ModuleFix{
+				{ModuleName: "OrdersModule", UnusedImports: tt.unused, MissingImports: tt.missing},
+			})
+			if err != nil {
+				t.Fatalf("PlanEdits failed: %v", err)
+			}
+			result, err := fixing.ApplyEdits([]byte(tt.sourceCode), edits)
+			if err != nil {
+				t.Fatalf("ApplyEdits failed: %v", err)
+			}
+
+			if string(result) != tt.expectedResult {
+				t.Errorf("Result mismatch\nGot:\n%s\n\nExpected:\n%s", result, tt.expectedResult)
+			}
+		})
+	}
+}
+
+func TestRevertEdits(t *testing.T) {
+	source := []byte("[A, B, C]")
+	edits := []fixing.Edit{
+		{Start: 1, End: 4, Reason: "remove A"},
+		{Start: 7, End: 8, Replacement: "D, E"},
+	}
+
+	fixed, err := fixing.ApplyEdits(source, edits)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	reverted, err := fixing.RevertEdits(source, edits)
+	if err != nil {
+		t.Fatalf("RevertEdits failed: %v", err)
+	}
+	original, err := fixing.ApplyEdits(fixed, reverted)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(original) != string(source) {
+		t.Errorf("Expected %q, got %q", source, original)
+	}
+}
@@ -0,0 +1,361 @@
+package fixing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// insertModuleImports returns the edit adding the missing modules to a
+// module's imports array, creating the array when the metadata has none. The
+// modules already imported are skipped, and nothing is inserted when the
+// imports are not written inline in the decorator. removed holds the start
+// bytes of the elements other edits remove.
+func insertModuleImports(declaration *parser.ModuleDeclaration, missing []MissingImport, sourceCode []byte, removed map[uint32]bool) (Edit, []MissingImport) {
+	imported := toSet(declaration.ImportedModuleNames())
+	var inserted []MissingImport
+	var names []string
+	for _, module := range missing {
+		if imported[module.Name] {
+			continue
+		}
+		imported[module.Name] = true
+		inserted = append(inserted, module)
+		names = append(names, module.Name)
+	}
+	if len(names) == 0 {
+		return Edit{}, nil
+	}
+	reason := fmt.Sprintf("add missing import %s to %s", strings.Join(names, ", "), declaration.Name)
+
+	if array := declaration.ArrayNode("imports"); array != nil {
+		edit := arrayInsertion(array, names, sourceCode, removed)
+		edit.Reason = reason
+		return edit, inserted
+	}
+
+	// Imports that exist but are not an inline array, e.g. a const
+	// reference, cannot be extended safely
+	metadata := declaration.Metadata
+	if !declaration.InDecorator(metadata) || metadataHasKey(metadata, sourceCode, "imports") {
+		return Edit{}, nil
+	}
+	edit := propertyInsertion(metadata, fmt.Sprintf("imports: [%s]", strings.Join(names, ", ")), sourceCode)
+	edit.Reason = reason
+	return edit, inserted
+}
+
+// arrayInsertion returns the edit appending elements to an array, following
+// its inline or one-element-per-line layout. Elements in removed are not
+// appended to.
+func arrayInsertion(array *sitter.Node, names []string, sourceCode []byte, removed map[uint32]bool) Edit {
+	var last, lastRemoved *sitter.Node
+	for i := 0; i < int(array.NamedChildCount()); i++ {
+		element := array.NamedChild(i)
+		if element.Type() == "comment" {
+			continue
+		}
+		if removed[element.StartByte()] {
+			lastRemoved = element
+			continue
+		}
+		last = element
+	}
+	closing := int(array.EndByte()) - 1
+	if last == nil {
+		// Every element is removed: keep the one-element-per-line layout
+		if lastRemoved != nil && lastRemoved.StartPoint().Row > array.StartPoint().Row {
+			if lineStart, ok := lineStartBefore(sourceCode, closing); ok {
+				indent := lineIndent(sourceCode, int(lastRemoved.StartByte()))
+				var lines strings.Builder
+				for _, name := range names {
+					lines.WriteString(indent + name + ",\n")
+				}
+				return Edit{Start: lineStart, End: lineStart, Replacement: lines.String()}
+			}
+		}
+		// Empty array: fill in right before the closing bracket
+		return Edit{Start: closing, End: closing, Replacement: strings.Join(names, ", ")}
+	}
+
+	next := last.NextSibling()
+	hasComma := next != nil && next.Type() == ","
+
+	if last.StartPoint().Row == array.StartPoint().Row {
+		// The separator after last goes away with the removed elements
+		// following it, so insert right after last
+		if hasComma && !(lastRemoved != nil && lastRemoved.StartByte() > last.StartByte()) {
+			position := int(next.EndByte())
+			return Edit{Start: position, End: position, Replacement: " " + strings.Join(names, ", ") + ","}
+		}
+		position := int(last.EndByte())
+		return Edit{Start: position, End: position, Replacement: ", " + strings.Join(names, ", ")}
+	}
+
+	indent := lineIndent(sourceCode, int(last.StartByte()))
+	if hasComma {
+		if lineStart, ok := lineStartBefore(sourceCode, closing); ok {
+			// Insert whole lines before the closing bracket
+			var lines strings.Builder
+			for _, name := range names {
+				lines.WriteString(indent + name + ",\n")
+			}
+			return Edit{Start: lineStart, End: lineStart, Replacement: lines.String()}
+		}
+		var lines strings.Builder
+		for _, name := range names {
+			lines.WriteString("\n" + indent + name + ",")
+		}
+		position := int(next.EndByte())
+		return Edit{Start: position, End: position, Replacement: lines.String()}
+	}
+
+	var lines strings.Builder
+	for _, name := range names {
+		lines.WriteString(",\n" + indent + name)
+	}
+	position := int(last.EndByte())
+	return Edit{Start: position, End: position, Replacement: lines.String()}
+}
+
+// lineStartBefore returns the start of the line holding position when only
+// horizontal space precedes it on that line
+func lineStartBefore(sourceCode []byte, position int) (int, bool) {
+	lineStart := position
+	for lineStart > 0 && isHorizontalSpace(sourceCode[lineStart-1]) {
+		lineStart--
+	}
+	return lineStart, lineStart == 0 || sourceCode[lineStart-1] == '\n'
+}
+
+// ignoreComment marks an element the linter leaves alone
+const ignoreComment = "// nestjs-module-lint-disable-line"
+
+// ignoreCommentEdit returns the edit adding an ignore comment after an array
+// element and its separator. An element followed by more code on its line is
+// moved onto a line of its own by breaking the line after the comment.
+func ignoreCommentEdit(element *sitter.Node, sourceCode []byte) Edit {
+	position := int(element.EndByte())
+	if next := element.NextSibling(); next != nil && next.Type() == "," {
+		position = int(next.EndByte())
+	}
+	end := position
+	for end < len(sourceCode) && isHorizontalSpace(sourceCode[end]) {
+		end++
+	}
+	if end == len(sourceCode) || sourceCode[end] == '\n' || sourceCode[end] == '\r' {
+		return Edit{Start: position, End: position, Replacement: " " + ignoreComment}
+	}
+	indent := lineIndent(sourceCode, int(element.StartByte())) + "  "
+	return Edit{Start: position, End: end, Replacement: " " + ignoreComment + "\n" + indent}
+}
+
+// propertyInsertion returns the edit adding a property as the first one of
+// an object literal
+func propertyInsertion(object *sitter.Node, property string, sourceCode []byte) Edit {
+	position := int(object.StartByte()) + 1
+	if object.NamedChildCount() == 0 {
+		return Edit{Start: position, End: int(object.EndByte()) - 1, Replacement: " " + property + " "}
+	}
+	first := object.NamedChild(0)
+	if first.StartPoint().Row > object.StartPoint().Row {
+		return Edit{Start: position, End: position, Replacement: "\n" + lineIndent(sourceCode, int(first.StartByte())) + property + ","}
+	}
+	return Edit{Start: position, End: position, Replacement: " " + property + ","}
+}
+
+// importStatementInsertions returns the edits importing the added modules,
+// merged into an existing value import from the same specifier when there is
+// one. Names the file already binds are skipped, and names it binds with a
+// type-only specifier lose their type modifier, since modules are values.
+func importStatementInsertions(root *sitter.Node, sourceCode []byte, added []MissingImport) ([]Edit, []ImportChange) {
+	bound := make(map[string]bool)
+	typeSpecifiers := make(map[string]*sitter.Node)
+	specifierSources := make(map[*sitter.Node]string)
+	var lastImport *sitter.Node
+	statementsBySpecifier := make(map[string]*sitter.Node)
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		statement := root.NamedChild(i)
+		if statement.Type() != "import_statement" {
+			continue
+		}
+		lastImport = statement
+		source := ""
+		if node := statement.ChildByFieldName("source"); node != nil {
+			source = stringContent(node, sourceCode)
+		}
+		typeOnly := typeModifier(statement) != nil
+		for _, binding := range importBindings(statement, sourceCode) {
+			if !typeOnly && binding.group != nil && typeModifier(binding.node) != nil {
+				typeSpecifiers[binding.name] = binding.node
+				specifierSources[binding.node] = source
+				continue
+			}
+			bound[binding.name] = true
+		}
+		// A type-only import statement cannot bind the modules as values
+		if _, exists := statementsBySpecifier[source]; source != "" && !typeOnly && !exists {
+			statementsBySpecifier[source] = statement
+		}
+	}
+
+	var edits []Edit
+	var changes []ImportChange
+
+	// Group the names by specifier, keeping the order they were added in
+	var specifiers []string
+	namesBySpecifier := make(map[string][]string)
+	for _, module := range added {
+		if bound[module.Name] {
+			continue
+		}
+		if specifier, ok := typeSpecifiers[module.Name]; ok {
+			bound[module.Name] = true
+			modifier := typeModifier(specifier)
+			edits = append(edits, Edit{
+				Start:  int(modifier.StartByte()),
+				End:    int(specifier.ChildByFieldName("name").StartByte()),
+				Reason: fmt.Sprintf("import %s as a value", module.Name),
+			})
+			changes = append(changes, ImportChange{Action: ImportModified, Specifier: specifierSources[specifier], Added: []string{module.Name}})
+			continue
+		}
+		bound[module.Name] = true
+		if _, exists := namesBySpecifier[module.Specifier]; !exists {
+			specifiers = append(specifiers, module.Specifier)
+		}
+		namesBySpecifier[module.Specifier] = append(namesBySpecifier[module.Specifier], module.Name)
+	}
+
+	var newStatements strings.Builder
+	quote := `"`
+	if lastImport != nil {
+		if source := lastImport.ChildByFieldName("source"); source != nil && strings.HasPrefix(source.Content(sourceCode), "'") {
+			quote = "'"
+		}
+	}
+	for _, specifier := range specifiers {
+		names := namesBySpecifier[specifier]
+		reason := fmt.Sprintf("import %s from %s", strings.Join(names, ", "), specifier)
+		if statement, exists := statementsBySpecifier[specifier]; exists {
+			if edit, ok := mergeIntoImport(statement, names, sourceCode); ok {
+				edit.Reason = reason
+				edits = append(edits, edit)
+				changes = append(changes, ImportChange{Action: ImportModified, Specifier: specifier, Added: names})
+				continue
+			}
+		}
+		changes = append(changes, ImportChange{Action: ImportAdded, Specifier: specifier, Added: names})
+		newStatements.WriteString(fmt.Sprintf("import { %s } from %s%s%s;\n", strings.Join(names, ", "), quote, specifier, quote))
+	}
+
+	if newStatements.Len() > 0 {
+		reason := "add import statements for the missing imports"
+		if lastImport == nil {
+			edits = append(edits, Edit{Start: 0, End: 0, Replacement: newStatements.String(), Reason: reason})
+		} else {
+			// Insert after the line holding the last import statement
+			position := int(lastImport.EndByte())
+			for position < len(sourceCode) && sourceCode[position] != '\n' {
+				position++
+			}
+			if position < len(sourceCode) {
+				edits = append(edits, Edit{Start: position + 1, End: position + 1, Replacement: newStatements.String(), Reason: reason})
+			} else {
+				edits = append(edits, Edit{Start: position, End: position, Replacement: "\n" + strings.TrimSuffix(newStatements.String(), "\n"), Reason: reason})
+			}
+		}
+	}
+	return edits, changes
+}
+
+// mergeIntoImport returns the edit adding named imports to an existing
+// import statement with named imports or a default import
+func mergeIntoImport(statement *sitter.Node, names []string, sourceCode []byte) (Edit, bool) {
+	for i := 0; i < int(statement.NamedChildCount()); i++ {
+		clause := statement.NamedChild(i)
+		if clause.Type() != "import_clause" {
+			continue
+		}
+		var defaultImport *sitter.Node
+		for j := 0; j < int(clause.NamedChildCount()); j++ {
+			child := clause.NamedChild(j)
+			switch child.Type() {
+			case "named_imports":
+				var last *sitter.Node
+				for k := 0; k < int(child.NamedChildCount()); k++ {
+					if specifier := child.NamedChild(k); specifier.Type() == "import_specifier" {
+						last = specifier
+					}
+				}
+				if last == nil {
+					position := int(child.StartByte()) + 1
+					return Edit{Start: position, End: int(child.EndByte()) - 1, Replacement: " " + strings.Join(names, ", ") + " "}, true
+				}
+				position := int(last.EndByte())
+				return Edit{Start: position, End: position, Replacement: ", " + strings.Join(names, ", ")}, true
+			case "namespace_import":
+				return Edit{}, false
+			case "identifier":
+				defaultImport = child
+			}
+		}
+		if defaultImport != nil {
+			position := int(defaultImport.EndByte())
+			return Edit{Start: position, End: position, Replacement: ", { " + strings.Join(names, ", ") + " }"}, true
+		}
+	}
+	return Edit{}, false
+}
+
+// typeModifier returns the type keyword making an import statement or an
+// import specifier type-only, or nil for value imports
+func typeModifier(n *sitter.Node) *sitter.Node {
+	for i := 0; i < int(n.ChildCount()); i++ {
+		if child := n.Child(i); !child.IsNamed() && child.Type() == "type" {
+			return child
+		}
+	}
+	return nil
+}
+
+// metadataHasKey reports whether an object literal has a property named key
+func metadataHasKey(object *sitter.Node, sourceCode []byte, key string) bool {
+	for i := 0; i < int(object.NamedChildCount()); i++ {
+		child := object.NamedChild(i)
+		switch child.Type() {
+		case "pair":
+			if name := child.ChildByFieldName("key"); name != nil && strings.Trim(name.Content(sourceCode), `"'`) == key {
+				return true
+			}
+		case "shorthand_property_identifier":
+			if child.Content(sourceCode) == key {
+				return true
+			}
+		case "spread_element":
+			// A spread might provide the key
+			return true
+		}
+	}
+	return false
+}
+
+// lineIndent returns the whitespace that starts the line holding offset
+func lineIndent(sourceCode []byte, offset int) string {
+	lineStart := offset
+	for lineStart > 0 && sourceCode[lineStart-1] != '\n' {
+		lineStart--
+	}
+	end := lineStart
+	for end < len(sourceCode) && isHorizontalSpace(sourceCode[end]) {
+		end++
+	}
+	return string(sourceCode[lineStart:end])
+}
+
+// stringContent returns the value of a string literal node
+func stringContent(n *sitter.Node, sourceCode []byte) string {
+	return strings.Trim(n.Content(sourceCode), "\"'`")
+}
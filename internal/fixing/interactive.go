@@ -0,0 +1,197 @@
+package fixing
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Decision is a reviewer's verdict on a proposed removal
+type Decision int
+
+const (
+	// DecisionApply removes the import
+	DecisionApply Decision = iota
+	// DecisionSkip keeps the import
+	DecisionSkip
+	// DecisionSkipFile keeps the import and the remaining ones of its file
+	DecisionSkipFile
+	// DecisionIgnore keeps the import and marks it with an ignore comment
+	DecisionIgnore
+	// DecisionQuit keeps the import and every remaining one
+	DecisionQuit
+)
+
+// Review describes a proposed removal of an unused import
+type Review struct {
+	File   string
+	Module string
+	Import string
+	Reason string
+	// Frame shows the source around the import, if it was found
+	Frame string
+}
+
+// Prompter asks a reviewer for decisions on proposed removals
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewPrompter creates a prompter reading answers from in and writing
+// prompts to out
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Ask shows a proposed removal and reads the decision, asking again on an
+// unknown answer. The end of the input counts as quitting.
+func (p *Prompter) Ask(review Review) (Decision, error) {
+	fmt.Fprintf(p.out, "\n%s (%s) imports %s\n", review.Module, review.File, review.Import)
+	fmt.Fprintf(p.out, "Reason: %s\n", review.Reason)
+	if review.Frame != "" {
+		fmt.Fprint(p.out, review.Frame)
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Remove %s? [y]es, [n]o, [s]kip file, [i]gnore with comment, [q]uit: ", review.Import)
+		answer, err := p.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return DecisionQuit, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return DecisionApply, nil
+		case "n", "no":
+			return DecisionSkip, nil
+		case "s", "skip":
+			return DecisionSkipFile, nil
+		case "i", "ignore":
+			return DecisionIgnore, nil
+		case "q", "quit":
+			return DecisionQuit, nil
+		}
+		if err == io.EOF {
+			fmt.Fprintln(p.out)
+			return DecisionQuit, nil
+		}
+		fmt.Fprintf(p.out, "Unknown answer %q\n", strings.TrimSpace(answer))
+	}
+}
+
+// FixInteractive analyzes directories or files and asks for a decision on
+// each unused import before applying the accepted ones as a single run.
+// Quitting ends the review of every path. Findings of other rules are left
+// for a batch fix.
+func (w *Workflow) FixInteractive(paths []string, prompter *Prompter) ([]*FixResult, error) {
+	reports, err := w.analyzePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewed []*analysis.ModuleAnalysisResult
+	ignored := make(map[*analysis.ModuleAnalysisResult][]string)
+	skippedFiles := make(map[string]bool)
+	sources := make(map[string][]byte)
+	quit := false
+	for _, report := range reports {
+		if quit {
+			break
+		}
+		accepted := &analysis.ModuleAnalysisResult{
+			ModuleName: report.ModuleName,
+			FilePath:   report.FilePath,
+		}
+		for _, name := range report.UnusedImports {
+			if quit || skippedFiles[report.FilePath] {
+				break
+			}
+			if !w.selector.selects(report.FilePath, report.ModuleName, analysis.RuleUnusedImport, name) {
+				continue
+			}
+			if _, ok := sources[report.FilePath]; !ok {
+				sources[report.FilePath], _ = os.ReadFile(report.FilePath)
+			}
+			decision, err := prompter.Ask(Review{
+				File:   report.FilePath,
+				Module: report.ModuleName,
+				Import: name,
+				Reason: fmt.Sprintf("no provider or controller of %s uses anything %s exports, and %s does not re-export it", report.ModuleName, name, report.ModuleName),
+				Frame:  w.importFrame(sources[report.FilePath], report.ModuleName, name),
+			})
+			if err != nil {
+				return nil, err
+			}
+			switch decision {
+			case DecisionApply:
+				accepted.UnusedImports = append(accepted.UnusedImports, name)
+			case DecisionIgnore:
+				ignored[accepted] = append(ignored[accepted], name)
+			case DecisionSkipFile:
+				skippedFiles[report.FilePath] = true
+			case DecisionQuit:
+				quit = true
+			}
+		}
+		reviewed = append(reviewed, accepted)
+	}
+
+	plan, err := w.planReports(reviewed, ignored)
+	if err != nil {
+		return nil, err
+	}
+	return w.apply(plan, nil, 1)
+}
+
+// importFrame returns the code frame around a module's import element
+func (w *Workflow) importFrame(sourceCode []byte, moduleName, name string) string {
+	normalized := detectEncoding(sourceCode).normalize(sourceCode)
+	tree, err := sitter.ParseCtx(context.Background(), normalized, w.fixer.lang)
+	if err != nil {
+		return ""
+	}
+	declarations, err := parser.ParseModuleDeclarations(tree, normalized, w.fixer.moduleDecorators...)
+	if err != nil {
+		return ""
+	}
+	for _, declaration := range declarations {
+		if declaration.Name != moduleName {
+			continue
+		}
+		for _, element := range declaration.ArrayElements("imports") {
+			if element.Content(normalized) == name {
+				return CodeFrame(normalized, int(element.StartPoint().Row), 2)
+			}
+		}
+	}
+	return ""
+}
+
+// CodeFrame returns the lines around a zero based row, numbered and with the
+// row marked
+func CodeFrame(sourceCode []byte, row, context int) string {
+	lines := strings.Split(string(sourceCode), "\n")
+	if row < 0 || row >= len(lines) {
+		return ""
+	}
+	first := max(row-context, 0)
+	last := min(row+context, len(lines)-1)
+	width := len(fmt.Sprint(last + 1))
+
+	var frame strings.Builder
+	for i := first; i <= last; i++ {
+		marker := " "
+		if i == row {
+			marker = ">"
+		}
+		fmt.Fprintf(&frame, "%s %*d | %s\n", marker, width, i+1, lines[i])
+	}
+	return frame.String()
+}
@@ -0,0 +1,160 @@
+package fixing_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestPrompter_Ask(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected fixing.Decision
+	}{
+		{name: "apply", input: "y\n", expected: fixing.DecisionApply},
+		{name: "skip", input: "no\n", expected: fixing.DecisionSkip},
+		{name: "skip file", input: "s\n", expected: fixing.DecisionSkipFile},
+		{name: "ignore", input: "I\n", expected: fixing.DecisionIgnore},
+		{name: "quit", input: "q\n", expected: fixing.DecisionQuit},
+		{name: "ask again on unknown answer", input: "maybe\ny\n", expected: fixing.DecisionApply},
+		{name: "end of input quits", input: "", expected: fixing.DecisionQuit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			prompter := fixing.NewPrompter(strings.NewReader(tt.input), &out)
+
+			decision, err := prompter.Ask(fixing.Review{
+				File:   "app.module.ts",
+				Module: "AppModule",
+				Import: "UnusedModule",
+				Reason: "unused",
+			})
+			if err != nil {
+				t.Fatalf("Ask failed: %v", err)
+			}
+			if decision != tt.expected {
+				t.Errorf("Expected decision %v, got %v", tt.expected, decision)
+			}
+			if !strings.Contains(out.String(), "AppModule (app.module.ts) imports UnusedModule") {
+				t.Errorf("Expected the prompt to describe the import, got:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestWorkflow_FixInteractive(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "app.module.ts")
+
+	sourceCode := `import { Module } from "@nestjs/common";
+import { FirstModule } from "./first.module";
+import { SecondModule } from "./second.module";
+import { ThirdModule } from "./third.module";
+
+@Module({
+  imports: [
+    FirstModule,
+    SecondModule,
+    ThirdModule,
+  ],
+})
+export class AppModule {}
+`
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{{
+		{ModuleName: "AppModule", FilePath: testFile, UnusedImports: []string{"FirstModule", "SecondModule", "ThirdModule"}},
+	}}}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil)
+
+	// Remove FirstModule, ignore SecondModule and keep ThirdModule
+	var out bytes.Buffer
+	prompter := fixing.NewPrompter(strings.NewReader("y\ni\nn\n"), &out)
+	results, err := workflow.FixInteractive([]string{testFile}, prompter)
+	if err != nil {
+		t.Fatalf("FixInteractive failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].RemovedElements) != 1 || len(results[0].IgnoredElements) != 1 {
+		t.Errorf("Expected one removed and one ignored element, got %v", results)
+	}
+
+	expected := `import { Module } from "@nestjs/common";
+import { SecondModule } from "./second.module";
+import { ThirdModule } from "./third.module";
+
+@Module({
+  imports: [
+    SecondModule, // nestjs-module-lint-disable-line
+    ThirdModule,
+  ],
+})
+export class AppModule {}
+`
+	fixed, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read fixed file: %v", err)
+	}
+	if string(fixed) != expected {
+		t.Errorf("Result mismatch\nGot:\n%s\n\nExpected:\n%s", fixed, expected)
+	}
+	if !strings.Contains(out.String(), ">  8 |     FirstModule,") {
+		t.Errorf("Expected a code frame marking the import, got:\n%s", out.String())
+	}
+}
+
+func TestCodeFrame(t *testing.T) {
+	source := []byte("a\nb\nc\nd\n")
+
+	expected := "  1 | a\n> 2 | b\n  3 | c\n"
+	if frame := fixing.CodeFrame(source, 1, 1); frame != expected {
+		t.Errorf("Expected frame:\n%s\ngot:\n%s", expected, frame)
+	}
+}
+
+func TestWorkflow_FixInteractive_QuitEndsEveryPath(t *testing.T) {
+	tempDir := t.TempDir()
+	sourceCode := `import { UnusedModule } from "./unused.module";
+
+@Module({ imports: [UnusedModule] })
+export class %s {}
+`
+	analyzer := &pathAnalyzer{results: make(map[string][]*analysis.ModuleAnalysisResult)}
+	var paths []string
+	for _, name := range []string{"AppModule", "UsersModule"} {
+		path := filepath.Join(tempDir, strings.ToLower(name)+".ts")
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(sourceCode, name)), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		analyzer.results[path] = []*analysis.ModuleAnalysisResult{{ModuleName: name, FilePath: path, UnusedImports: []string{"UnusedModule"}}}
+		paths = append(paths, path)
+	}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil)
+
+	// The answer after quitting must not be read for the second path
+	var out bytes.Buffer
+	prompter := fixing.NewPrompter(strings.NewReader("q\ny\n"), &out)
+	results, err := workflow.FixInteractive(paths, prompter)
+	if err != nil {
+		t.Fatalf("FixInteractive failed: %v", err)
+	}
+	if prompts := strings.Count(out.String(), "Remove UnusedModule?"); prompts != 1 {
+		t.Errorf("Expected a single prompt, got %d:\n%s", prompts, out.String())
+	}
+	for _, result := range results {
+		if result.Fixed {
+			t.Errorf("Expected no file to be fixed after quitting, got %+v", result)
+		}
+	}
+}
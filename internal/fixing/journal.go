@@ -0,0 +1,223 @@
+package fixing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+)
+
+// ErrNoJournal is returned when there is no fix run to undo
+var ErrNoJournal = errors.New("no fix run to undo")
+
+// maxJournals is how many journals are kept per project; saving a journal
+// removes the oldest ones beyond it
+const maxJournals = 20
+
+// Journal records the file changes of a fix run so the run can be undone
+type Journal struct {
+	CreatedAt time.Time      `json:"createdAt"`
+	Files     []JournalEntry `json:"files"`
+}
+
+// JournalEntry records the change made to a single file
+type JournalEntry struct {
+	// Path is the absolute path of the file
+	Path         string `json:"path"`
+	OriginalHash string `json:"originalHash"`
+	FixedHash    string `json:"fixedHash"`
+	// Diff is the unified diff of the change, for reference
+	Diff string `json:"diff"`
+	// Revert holds the edits turning the fixed file back into the original
+	Revert []Edit `json:"revert"`
+}
+
+// NewJournal creates an empty journal
+func NewJournal() *Journal {
+	return &Journal{CreatedAt: time.Now().UTC()}
+}
+
+// Record adds the file changes of a plan to the journal. A file fixed in
+// several passes gets one entry per pass.
+func (j *Journal) Record(plan *Plan) error {
+	for _, file := range plan.Files {
+		path, err := filepath.Abs(file.Path)
+		if err != nil {
+			return err
+		}
+		revert, err := RevertEdits(file.Original, file.Edits)
+		if err != nil {
+			return fmt.Errorf("failed to record %s: %w", file.Path, err)
+		}
+		j.Files = append(j.Files, JournalEntry{
+			Path:         path,
+			OriginalHash: contentHash(file.Original),
+			FixedHash:    contentHash(file.Fixed),
+			Diff:         UnifiedDiff(file.Path, file.Original, file.Fixed),
+			Revert:       revert,
+		})
+	}
+	return nil
+}
+
+// JournalDir returns the directory holding the fix journals of a project,
+// under the user's cache directory
+func JournalDir(workingDirectory string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+	absPath, err := filepath.Abs(workingDirectory)
+	if err != nil {
+		return "", err
+	}
+	project := contentHash([]byte(absPath))[:16]
+	return filepath.Join(cacheDir, "nestjs-module-lint", "journal", project), nil
+}
+
+// SaveJournal writes a journal to the journal directory and removes the
+// oldest journals beyond the last maxJournals
+func SaveJournal(dir string, journal *Journal) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d.json", journal.CreatedAt.UnixNano())
+	if err := filesystem.WriteFileAtomic(filepath.Join(dir, name), data); err != nil {
+		return err
+	}
+
+	names, err := journalNames(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names[:max(len(names)-maxJournals, 0)] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("failed to remove old journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// UndoLastFix restores the files changed by the latest journaled fix run and
+// returns their paths. Nothing is restored when a file changed since the
+// fix. Files already back to their original content are skipped.
+func UndoLastFix(dir string) ([]string, error) {
+	journalPath, err := latestJournal(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(journalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	// Check every file before restoring any, so the undo is all or nothing.
+	// Entries are reverted newest first; an entry whose file still has its
+	// original content was never written.
+	entriesByPath := make(map[string][]JournalEntry)
+	var order []string
+	for _, entry := range journal.Files {
+		if _, ok := entriesByPath[entry.Path]; !ok {
+			order = append(order, entry.Path)
+		}
+		entriesByPath[entry.Path] = append(entriesByPath[entry.Path], entry)
+	}
+	restored := make(map[string][]byte)
+	for _, path := range order {
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		changed := false
+		entries := entriesByPath[path]
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+			switch contentHash(current) {
+			case entry.OriginalHash:
+				continue
+			case entry.FixedHash:
+			default:
+				return nil, fmt.Errorf("%s changed since the fix, not undoing", path)
+			}
+			current, err = ApplyEdits(current, entry.Revert)
+			if err != nil || contentHash(current) != entry.OriginalHash {
+				return nil, fmt.Errorf("failed to restore %s", path)
+			}
+			changed = true
+		}
+		if changed {
+			restored[path] = current
+		}
+	}
+
+	paths := make([]string, 0, len(restored))
+	for path := range restored {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := filesystem.WriteFileAtomic(path, restored[path]); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+
+	if err := os.Remove(journalPath); err != nil {
+		return nil, fmt.Errorf("failed to remove journal: %w", err)
+	}
+	return paths, nil
+}
+
+// latestJournal returns the path of the most recent journal in dir
+func latestJournal(dir string) (string, error) {
+	names, err := journalNames(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNoJournal
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", ErrNoJournal
+	}
+	return filepath.Join(dir, names[len(names)-1]), nil
+}
+
+// journalNames returns the file names of the journals in dir, oldest first
+func journalNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	// Journal names are timestamps of equal length, so they sort by time
+	sort.Strings(names)
+	return names, nil
+}
+
+// contentHash returns the hex encoded SHA-256 hash of content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package fixing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Element kinds of an ElementChange
+const (
+	ElementImport        = "import"
+	ElementFeatureEntity = "feature_entity"
+)
+
+// Import statement actions of an ImportChange
+const (
+	ImportRemoved  = "removed"
+	ImportModified = "modified"
+	ImportAdded    = "added"
+)
+
+// ElementChange is an element removed from, added to or marked in a module's
+// metadata
+type ElementChange struct {
+	Module string `json:"module"`
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+}
+
+// ImportChange is an import statement a fix removes, modifies or adds
+type ImportChange struct {
+	Action    string   `json:"action"`
+	Specifier string   `json:"specifier"`
+	Removed   []string `json:"removed,omitempty"`
+	Added     []string `json:"added,omitempty"`
+}
+
+// Changes describes what the edits of a fix change
+type Changes struct {
+	RemovedElements  []ElementChange
+	AddedElements    []ElementChange
+	IgnoredElements  []ElementChange
+	ImportStatements []ImportChange
+}
+
+// FixResult describes what a fix run changed in a file
+type FixResult struct {
+	File             string          `json:"file"`
+	Fixed            bool            `json:"fixed"`
+	RemovedElements  []ElementChange `json:"removed_elements"`
+	AddedElements    []ElementChange `json:"added_elements,omitempty"`
+	IgnoredElements  []ElementChange `json:"ignored_elements,omitempty"`
+	ImportStatements []ImportChange  `json:"import_statements"`
+	Skipped          []SkippedFix    `json:"skipped,omitempty"`
+	// Error explains why the file was left unchanged, e.g. a fix that was
+	// rolled back
+	Error string `json:"error,omitempty"`
+	// Diff is the unified diff of every change made to the file
+	Diff string `json:"-"`
+}
+
+// newFixResult creates an empty result, so JSON lists are never null
+func newFixResult(file string) *FixResult {
+	return &FixResult{
+		File:             file,
+		RemovedElements:  []ElementChange{},
+		ImportStatements: []ImportChange{},
+	}
+}
+
+// addChanges records the changes of a fix pass written to the file
+func (r *FixResult) addChanges(changes Changes) {
+	r.Fixed = true
+	r.RemovedElements = append(r.RemovedElements, changes.RemovedElements...)
+	r.AddedElements = append(r.AddedElements, changes.AddedElements...)
+	r.IgnoredElements = append(r.IgnoredElements, changes.IgnoredElements...)
+	r.ImportStatements = append(r.ImportStatements, changes.ImportStatements...)
+}
+
+// CommitMessage describes the changes of a fix run, for a commit made after
+// it
+func CommitMessage(results []*FixResult) string {
+	subject := "Remove unused NestJS module imports"
+	var body strings.Builder
+	for _, result := range results {
+		if !result.Fixed {
+			continue
+		}
+		if len(result.AddedElements) > 0 || len(result.IgnoredElements) > 0 {
+			subject = "Fix NestJS module imports"
+		}
+		body.WriteString(fmt.Sprintf("\n%s:\n", result.File))
+		for _, element := range result.RemovedElements {
+			body.WriteString(fmt.Sprintf("- remove %s from %s\n", element.Name, element.Module))
+		}
+		for _, element := range result.AddedElements {
+			body.WriteString(fmt.Sprintf("- add %s to %s\n", element.Name, element.Module))
+		}
+		for _, element := range result.IgnoredElements {
+			body.WriteString(fmt.Sprintf("- ignore %s in %s\n", element.Name, element.Module))
+		}
+	}
+	return subject + "\n\nGenerated by nestjs-module-lint --fix.\n" + body.String()
+}
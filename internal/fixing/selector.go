@@ -0,0 +1,123 @@
+package fixing
+
+import (
+	"fmt"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
+)
+
+// Fix types, grouping fixes by what they do to a module
+const (
+	FixTypeRemove = "remove"
+	FixTypeAdd    = "add"
+)
+
+// fixTypes maps the rules the fixer can fix to the type of their fix
+var fixTypes = map[string]string{
+	analysis.RuleUnusedImport:        FixTypeRemove,
+	analysis.RuleUnusedFeatureEntity: FixTypeRemove,
+	analysis.RuleMissingImport:       FixTypeAdd,
+}
+
+// notSelectedReason explains why a finding outside of the selector is kept
+const notSelectedReason = "not selected for fixing"
+
+// Selector limits a fix to the findings it matches. Every non-empty list must
+// match; an empty selector matches everything.
+type Selector struct {
+	// Modules lists module names, e.g. AppModule
+	Modules []string
+	// Imports lists the names of imports, entities or modules to add
+	Imports []string
+	// Rules lists rule IDs, e.g. unused-import
+	Rules []string
+	// Paths lists globs of module files, e.g. src/users/**
+	Paths []string
+	// Types lists fix types, FixTypeRemove or FixTypeAdd
+	Types []string
+}
+
+// Validate checks that the selected rules have fixes and the fix types exist
+func (s Selector) Validate() error {
+	for _, rule := range s.Rules {
+		if _, ok := analysis.RuleByID(rule); !ok {
+			return fmt.Errorf("unknown rule %q", rule)
+		}
+		if _, ok := fixTypes[rule]; !ok {
+			return fmt.Errorf("rule %q has no fix", rule)
+		}
+	}
+	for _, fixType := range s.Types {
+		if fixType != FixTypeRemove && fixType != FixTypeAdd {
+			return fmt.Errorf("unknown fix type %q, expected %s or %s", fixType, FixTypeRemove, FixTypeAdd)
+		}
+	}
+	return nil
+}
+
+// selects reports whether the selector matches the finding of a rule about
+// name in a module
+func (s Selector) selects(filePath, moduleName, rule, name string) bool {
+	if len(s.Modules) > 0 && !contains(s.Modules, moduleName) {
+		return false
+	}
+	if len(s.Imports) > 0 && !contains(s.Imports, name) {
+		return false
+	}
+	if len(s.Rules) > 0 && !contains(s.Rules, rule) {
+		return false
+	}
+	if len(s.Types) > 0 && !contains(s.Types, fixTypes[rule]) {
+		return false
+	}
+	if len(s.Paths) > 0 {
+		for _, pattern := range s.Paths {
+			if filesystem.MatchGlob(pattern, filePath) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// filter drops the parts of a module fix the selector does not match and
+// returns them as skipped
+func (s Selector) filter(filePath string, fix ModuleFix) (ModuleFix, []SkippedFix) {
+	var skipped []SkippedFix
+	keep := func(rule string, names []string) []string {
+		var kept []string
+		for _, name := range names {
+			if s.selects(filePath, fix.ModuleName, rule, name) {
+				kept = append(kept, name)
+				continue
+			}
+			skipped = append(skipped, SkippedFix{File: filePath, Module: fix.ModuleName, Name: name, Reason: notSelectedReason})
+		}
+		return kept
+	}
+
+	filtered := fix
+	filtered.UnusedImports = keep(analysis.RuleUnusedImport, fix.UnusedImports)
+	filtered.UnusedEntities = keep(analysis.RuleUnusedFeatureEntity, fix.UnusedEntities)
+	filtered.MissingImports = nil
+	for _, missing := range fix.MissingImports {
+		if s.selects(filePath, fix.ModuleName, analysis.RuleMissingImport, missing.Name) {
+			filtered.MissingImports = append(filtered.MissingImports, missing)
+			continue
+		}
+		skipped = append(skipped, SkippedFix{File: filePath, Module: fix.ModuleName, Name: missing.Name, Reason: notSelectedReason})
+	}
+	return filtered, skipped
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
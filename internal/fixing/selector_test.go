@@ -0,0 +1,128 @@
+package fixing_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestWorkflow_Plan_Selector(t *testing.T) {
+	tempDir := t.TempDir()
+	usersFile := filepath.Join(tempDir, "users", "users.module.ts")
+	ordersFile := filepath.Join(tempDir, "orders", "orders.module.ts")
+
+	for _, file := range []struct{ path, module string }{
+		{usersFile, "UsersModule"},
+		{ordersFile, "OrdersModule"},
+	} {
+		sourceCode := `import { Module } from "@nestjs/common";
+import { LegacyAuthModule } from "../legacy-auth.module";
+import { CacheModule } from "../cache.module";
+
+@Module({ imports: [LegacyAuthModule, CacheModule] })
+export class ` + file.module + ` {}
+`
+		if err := os.MkdirAll(filepath.Dir(file.path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(file.path, []byte(sourceCode), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{{
+		{ModuleName: "OrdersModule", FilePath: ordersFile, UnusedImports: []string{"LegacyAuthModule", "CacheModule"}},
+		{ModuleName: "UsersModule", FilePath: usersFile, UnusedImports: []string{"LegacyAuthModule", "CacheModule"}},
+	}}}
+
+	tests := []struct {
+		name     string
+		selector fixing.Selector
+		// expected lists the removed imports by file
+		expected map[string][]string
+		skipped  int
+	}{
+		{
+			name:     "no selector",
+			expected: map[string][]string{ordersFile: {"LegacyAuthModule", "CacheModule"}, usersFile: {"LegacyAuthModule", "CacheModule"}},
+		},
+		{
+			name:     "only import",
+			selector: fixing.Selector{Imports: []string{"LegacyAuthModule"}},
+			expected: map[string][]string{ordersFile: {"LegacyAuthModule"}, usersFile: {"LegacyAuthModule"}},
+			skipped:  2,
+		},
+		{
+			name:     "only module",
+			selector: fixing.Selector{Modules: []string{"UsersModule"}},
+			expected: map[string][]string{usersFile: {"LegacyAuthModule", "CacheModule"}},
+			skipped:  2,
+		},
+		{
+			name:     "paths glob",
+			selector: fixing.Selector{Paths: []string{filepath.Join(tempDir, "orders", "**")}},
+			expected: map[string][]string{ordersFile: {"LegacyAuthModule", "CacheModule"}},
+			skipped:  2,
+		},
+		{
+			name:     "fix type without matching fixes",
+			selector: fixing.Selector{Types: []string{fixing.FixTypeAdd}},
+			expected: map[string][]string{},
+			skipped:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil).WithSelector(tt.selector)
+			plan, err := workflow.Plan(tempDir)
+			if err != nil {
+				t.Fatalf("Plan failed: %v", err)
+			}
+
+			removed := make(map[string][]string)
+			for _, file := range plan.Files {
+				for _, element := range file.Changes.RemovedElements {
+					removed[file.Path] = append(removed[file.Path], element.Name)
+				}
+			}
+			if !reflect.DeepEqual(removed, tt.expected) {
+				t.Errorf("Expected removed imports %v, got %v", tt.expected, removed)
+			}
+			if len(plan.Skipped) != tt.skipped {
+				t.Errorf("Expected %d skipped findings, got %v", tt.skipped, plan.Skipped)
+			}
+		})
+	}
+}
+
+func TestSelector_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector fixing.Selector
+		valid    bool
+	}{
+		{name: "fixable rule", selector: fixing.Selector{Rules: []string{analysis.RuleMissingImport}}, valid: true},
+		{name: "rule without fix", selector: fixing.Selector{Rules: []string{analysis.RuleUnusedQueue}}},
+		{name: "unknown rule", selector: fixing.Selector{Rules: []string{"no-such-rule"}}},
+		{name: "known fix type", selector: fixing.Selector{Types: []string{fixing.FixTypeRemove}}, valid: true},
+		{name: "unknown fix type", selector: fixing.Selector{Types: []string{"rewrite"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.selector.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected selector to be valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected selector to be invalid")
+			}
+		})
+	}
+}
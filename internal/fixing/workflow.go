@@ -1,32 +1,319 @@
 package fixing
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/filesystem"
 )
 
+// ImportPathFinder chooses the module specifier one file uses to import
+// another, e.g. a tsconfig paths alias or a relative path
+type ImportPathFinder interface {
+	ImportSpecifier(fromFile, targetFile string) string
+}
+
 // Workflow handles the complete fix process for a directory or file
 type Workflow struct {
-	analyzer analysis.ModuleAnalyzer
-	fixer    *Fixer
+	analyzer    analysis.ModuleAnalyzer
+	fixer       *Fixer
+	importPaths ImportPathFinder
+	journalDir  string
+	selector    Selector
+	writeGuard  WriteGuard
 }
 
-// NewWorkflow creates a new fix workflow
-func NewWorkflow(analyzer analysis.ModuleAnalyzer, fixer *Fixer) *Workflow {
+// WriteGuard is asked before a fix run first writes to a set of files and
+// returns the files it refuses, with the reason
+type WriteGuard func(paths []string) (map[string]string, error)
+
+// NewWorkflow creates a new fix workflow. Missing imports are only fixed when
+// importPaths is set.
+func NewWorkflow(analyzer analysis.ModuleAnalyzer, fixer *Fixer, importPaths ImportPathFinder) *Workflow {
 	return &Workflow{
-		analyzer: analyzer,
-		fixer:    fixer,
+		analyzer:    analyzer,
+		fixer:       fixer,
+		importPaths: importPaths,
+	}
+}
+
+// WithJournal records every fix run in dir, so it can be undone with
+// UndoLastFix
+func (w *Workflow) WithJournal(dir string) *Workflow {
+	w.journalDir = dir
+	return w
+}
+
+// WithSelector limits the fixes to the findings selector matches. The other
+// findings are reported as skipped.
+func (w *Workflow) WithSelector(selector Selector) *Workflow {
+	w.selector = selector
+	return w
+}
+
+// WithWriteGuard makes the workflow leave the files guard refuses unchanged
+func (w *Workflow) WithWriteGuard(guard WriteGuard) *Workflow {
+	w.writeGuard = guard
+	return w
+}
+
+// Plan is the set of changes a fix run makes
+type Plan struct {
+	Files []*FilePlan
+	// Skipped lists the findings the fix leaves for manual review
+	Skipped []SkippedFix
+	// RolledBack lists the files whose fix was rejected
+	RolledBack []RolledBackFile
+}
+
+// SkippedFix is a finding the fixer leaves alone, e.g. a missing import
+// exported by several modules
+type SkippedFix struct {
+	File       string   `json:"file"`
+	Module     string   `json:"module"`
+	Name       string   `json:"name"`
+	Reason     string   `json:"reason"`
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// String describes why the fix was skipped
+func (s SkippedFix) String() string {
+	return fmt.Sprintf("%s in %s (%s): %s", s.Name, s.Module, s.File, s.Reason)
+}
+
+// FilePlan holds the planned edits of a single file
+type FilePlan struct {
+	Path     string
+	Original []byte
+	Fixed    []byte
+	Edits    []Edit
+	// Changes describes the elements and import statements the edits change
+	Changes Changes
+}
+
+// PlannedEdit is an edit along with the file it applies to
+type PlannedEdit struct {
+	File string `json:"file"`
+	Edit
+}
+
+// Edits lists the planned edits of every file
+func (p *Plan) Edits() []PlannedEdit {
+	edits := make([]PlannedEdit, 0)
+	for _, file := range p.Files {
+		for _, edit := range file.Edits {
+			edits = append(edits, PlannedEdit{File: file.Path, Edit: edit})
+		}
+	}
+	return edits
+}
+
+// Diff returns the unified diff of every planned file change
+func (p *Plan) Diff() string {
+	var diff string
+	for _, file := range p.Files {
+		diff += UnifiedDiff(file.Path, file.Original, file.Fixed)
+	}
+	return diff
+}
+
+// HasChanges reports whether applying the plan changes any file
+func (p *Plan) HasChanges() bool {
+	return len(p.Files) > 0
+}
+
+// RolledBackFile is a file whose fix was rejected
+type RolledBackFile struct {
+	Path   string
+	Reason string
+}
+
+// String describes why the fix was rejected
+func (r RolledBackFile) String() string {
+	return fmt.Sprintf("%s: %s", r.Path, r.Reason)
+}
+
+// maxFixPasses caps how often FixPath re-analyzes the path it fixed
+const maxFixPasses = 5
+
+// FixPath handles the complete fix process for a directory or file and
+// returns what changed in each file. Fixing can expose more issues, e.g. an
+// import only the removed code used, or a change to a module affecting the
+// modules importing it, so every module of the path is analyzed and fixed
+// again until nothing changes or maxFixPasses is reached. Modules outside of
+// the path are not analyzed again.
+func (w *Workflow) FixPath(path string) ([]*FixResult, error) {
+	return w.FixPaths([]string{path})
+}
+
+// FixPaths fixes several directories or files like FixPath, as a single run
+// that is journaled, and undone, as a whole
+func (w *Workflow) FixPaths(paths []string) ([]*FixResult, error) {
+	reports, err := w.analyzePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := w.planReports(reports, nil)
+	if err != nil {
+		return nil, err
+	}
+	return w.apply(plan, paths, maxFixPasses)
+}
+
+// apply writes a plan and returns the results sorted by file. With more than
+// one pass, the paths are analyzed and fixed again until nothing changes or
+// the passes run out.
+func (w *Workflow) apply(plan *Plan, paths []string, passes int) ([]*FixResult, error) {
+	results := make(map[string]*FixResult)
+	result := func(file string) *FixResult {
+		if _, ok := results[file]; !ok {
+			results[file] = newFixResult(file)
+		}
+		return results[file]
+	}
+	for _, skipped := range plan.Skipped {
+		result(skipped.File).Skipped = append(result(skipped.File).Skipped, skipped)
+	}
+
+	journal := NewJournal()
+	originals := make(map[string][]byte)
+	fixed := make(map[string][]byte)
+	refused := make(map[string]bool)
+	for pass := 1; ; pass++ {
+		for _, rolledBack := range plan.RolledBack {
+			result(rolledBack.Path).Error = rolledBack.Reason
+		}
+		if !plan.HasChanges() {
+			break
+		}
+		if pass > passes {
+			for _, file := range plan.Files {
+				result(file.Path).Error = fmt.Sprintf("further fixes remain after %d passes, run the fix again", passes)
+			}
+			break
+		}
+
+		if err := w.guardFiles(plan, originals, refused, result); err != nil {
+			return nil, err
+		}
+		if !plan.HasChanges() {
+			break
+		}
+
+		// Record the pass before writing, so a partially written run can be
+		// undone
+		if w.journalDir != "" {
+			if err := journal.Record(plan); err != nil {
+				return nil, err
+			}
+			if err := SaveJournal(w.journalDir, journal); err != nil {
+				return nil, fmt.Errorf("failed to record fix journal: %w", err)
+			}
+		}
+
+		// Fix each file
+		for _, file := range plan.Files {
+			err := w.writeFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fix %s: %w", file.Path, err)
+			}
+			result(file.Path).addChanges(file.Changes)
+			if _, ok := originals[file.Path]; !ok {
+				originals[file.Path] = file.Original
+			}
+			fixed[file.Path] = file.Fixed
+		}
+
+		// A single pass applies exactly the reviewed plan
+		if passes == 1 {
+			break
+		}
+
+		reports, err := w.analyzePaths(paths)
+		if err != nil {
+			return nil, err
+		}
+		plan, err = w.planReports(reports, nil)
+		if err != nil {
+			return nil, err
+		}
+		// Findings are only reported as skipped once
+		plan.Skipped = nil
+	}
+
+	for file, content := range fixed {
+		results[file].Diff = UnifiedDiff(file, originals[file], content)
+	}
+
+	files := make([]string, 0, len(results))
+	for file := range results {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	sorted := make([]*FixResult, 0, len(files))
+	for _, file := range files {
+		sorted = append(sorted, results[file])
+	}
+	return sorted, nil
+}
+
+// guardFiles drops the files the write guard refuses from a plan and adds
+// them to refused. Files the run already wrote, listed in written, and files
+// it refused before are not checked again.
+func (w *Workflow) guardFiles(plan *Plan, written map[string][]byte, refused map[string]bool, result func(string) *FixResult) error {
+	if w.writeGuard == nil {
+		return nil
+	}
+	var paths []string
+	for _, file := range plan.Files {
+		if _, ok := written[file.Path]; !ok && !refused[file.Path] {
+			paths = append(paths, file.Path)
+		}
+	}
+	reasons := make(map[string]string)
+	if len(paths) > 0 {
+		var err error
+		if reasons, err = w.writeGuard(paths); err != nil {
+			return err
+		}
+	}
+
+	var allowed []*FilePlan
+	for _, file := range plan.Files {
+		if reason, ok := reasons[file.Path]; ok {
+			result(file.Path).Error = reason
+			refused[file.Path] = true
+			continue
+		}
+		if !refused[file.Path] {
+			allowed = append(allowed, file)
+		}
+	}
+	plan.Files = allowed
+	return nil
+}
+
+// Plan analyzes a directory or file and computes the fixes without writing
+// anything
+func (w *Workflow) Plan(path string) (*Plan, error) {
+	reports, err := w.analyze(path)
+	if err != nil {
+		return nil, err
 	}
+	return w.planReports(reports, nil)
 }
 
-// FixPath handles the complete fix process for a directory or file
-func (w *Workflow) FixPath(path string) error {
+// analyze analyzes a directory or file
+func (w *Workflow) analyze(path string) ([]*analysis.ModuleAnalysisResult, error) {
 	// Determine if it's a file or directory and analyze accordingly
 	info, err := os.Stat(path)
 	if err != nil {
-		return fmt.Errorf("cannot access path: %w", err)
+		return nil, fmt.Errorf("cannot access path: %w", err)
 	}
 
 	var reports []*analysis.ModuleAnalysisResult
@@ -37,48 +324,184 @@ func (w *Workflow) FixPath(path string) error {
 	}
 
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
+	return reports, nil
+}
 
-	if len(reports) == 0 {
-		fmt.Println("✓ No unused imports found - nothing to fix")
-		return nil
+// analyzePaths analyzes several directories or files. Modules found through
+// more than one path, e.g. a file inside a given directory, are kept once.
+func (w *Workflow) analyzePaths(paths []string) ([]*analysis.ModuleAnalysisResult, error) {
+	var reports []*analysis.ModuleAnalysisResult
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		pathReports, err := w.analyze(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range pathReports {
+			key := report.FilePath + "\x00" + report.ModuleName
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			reports = append(reports, report)
+		}
 	}
+	return reports, nil
+}
 
-	fmt.Printf("Found %d files with unused imports, fixing...\n", len(reports))
-
-	// Fix each file
+// planReports computes the fixes of a set of analysis results. ignored holds
+// the unused imports of a report to mark with an ignore comment instead.
+func (w *Workflow) planReports(reports []*analysis.ModuleAnalysisResult, ignored map[*analysis.ModuleAnalysisResult][]string) (*Plan, error) {
+	// Group the module fixes by file, so each file is edited once. Some
+	// rules, e.g. queue checks, report findings the fixer cannot remove.
+	plan := &Plan{}
+	fixesByFile := make(map[string][]ModuleFix)
 	for _, report := range reports {
-		err := w.fixFile(report.FilePath, report.UnusedImports)
+		fix := moduleFix(report)
+		fix.MissingImports, plan.Skipped = w.missingImports(report, plan.Skipped)
+		var unselected []SkippedFix
+		fix, unselected = w.selector.filter(report.FilePath, fix)
+		plan.Skipped = append(plan.Skipped, unselected...)
+		fix.IgnoredImports = ignored[report]
+		if fix.isEmpty() {
+			continue
+		}
+		fixesByFile[report.FilePath] = append(fixesByFile[report.FilePath], fix)
+	}
+
+	paths := make([]string, 0, len(fixesByFile))
+	for filePath := range fixesByFile {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		file, err := w.planFile(filePath, fixesByFile[filePath])
+		var rejected *rejectedFixError
+		if errors.As(err, &rejected) {
+			plan.RolledBack = append(plan.RolledBack, RolledBackFile{Path: filePath, Reason: rejected.reason})
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("failed to fix %s: %w", report.FilePath, err)
+			return nil, fmt.Errorf("failed to fix %s: %w", filePath, err)
+		}
+		if len(file.Edits) == 0 {
+			continue
 		}
-		fmt.Printf("✓ Fixed %s (removed: %v)\n", report.FilePath, report.UnusedImports)
+		plan.Files = append(plan.Files, file)
 	}
+	return plan, nil
+}
 
-	fmt.Printf("✓ Successfully fixed %d files\n", len(reports))
-	return nil
+// rejectedFixError is returned for a file whose fix cannot be applied
+// safely, leaving the file unchanged
+type rejectedFixError struct {
+	reason string
 }
 
-// fixFile fixes unused imports in a specific file
-func (w *Workflow) fixFile(filePath string, unusedModules []string) error {
+func (e *rejectedFixError) Error() string {
+	return e.reason
+}
+
+// planFile computes the edits fixing the modules of a file. The fix is
+// rejected when it cannot be applied or the result does not parse.
+func (w *Workflow) planFile(filePath string, fixes []ModuleFix) (*FilePlan, error) {
 	// Read the current file
-	sourceCode, err := os.ReadFile(filePath)
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Fixes are computed without the byte order mark and CRLF line endings,
+	// which are restored afterwards
+	encoding := detectEncoding(original)
+	sourceCode := encoding.normalize(original)
+
+	edits, changes, err := w.fixer.PlanChanges(sourceCode, fixes)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, &rejectedFixError{reason: err.Error()}
 	}
 
-	// Apply fixes
-	fixedCode, err := w.fixer.FixUnusedImports(sourceCode, unusedModules)
+	fixedCode, err := ApplyEdits(sourceCode, edits)
 	if err != nil {
-		return fmt.Errorf("failed to apply fixes: %w", err)
+		return nil, &rejectedFixError{reason: err.Error()}
+	}
+
+	if err := w.fixer.VerifySyntax(sourceCode, fixedCode); err != nil {
+		return nil, &rejectedFixError{reason: err.Error()}
 	}
 
-	// Write the fixed code back
-	err = os.WriteFile(filePath, fixedCode, 0644)
+	return &FilePlan{
+		Path:     filePath,
+		Original: original,
+		Fixed:    encoding.restore(fixedCode),
+		Edits:    encoding.restoreEdits(sourceCode, edits),
+		Changes:  changes,
+	}, nil
+}
+
+// writeFile writes a planned file change, keeping the file's mode
+func (w *Workflow) writeFile(file *FilePlan) error {
+	err := filesystem.WriteFileAtomic(file.Path, file.Fixed)
 	if err != nil {
 		return fmt.Errorf("failed to write fixed file: %w", err)
 	}
 
 	return nil
 }
+
+// moduleFix builds the fixer input from an analysis result
+func moduleFix(report *analysis.ModuleAnalysisResult) ModuleFix {
+	var entities []string
+	for _, finding := range report.FindingsByRule(analysis.RuleUnusedFeatureEntity) {
+		entities = append(entities, finding.Name)
+	}
+	return ModuleFix{
+		ModuleName:     report.ModuleName,
+		UnusedImports:  report.UnusedImports,
+		UnusedEntities: entities,
+	}
+}
+
+// missingImports resolves the modules to import for the missing import
+// findings of a module. Findings with several candidate modules are appended
+// to skipped instead.
+func (w *Workflow) missingImports(report *analysis.ModuleAnalysisResult, skipped []SkippedFix) ([]MissingImport, []SkippedFix) {
+	if w.importPaths == nil {
+		return nil, skipped
+	}
+
+	var missing []MissingImport
+	for _, finding := range report.FindingsByRule(analysis.RuleMissingImport) {
+		if len(finding.Candidates) != 1 {
+			var candidates []string
+			for _, candidate := range finding.Candidates {
+				candidates = append(candidates, candidate.Name)
+			}
+			skipped = append(skipped, SkippedFix{
+				File:       report.FilePath,
+				Module:     report.ModuleName,
+				Name:       finding.Name,
+				Reason:     "exported by several modules: " + strings.Join(candidates, ", "),
+				Candidates: candidates,
+			})
+			continue
+		}
+		candidate := finding.Candidates[0]
+		fromFile, err := filepath.Abs(report.FilePath)
+		if err != nil {
+			continue
+		}
+		targetFile, err := filepath.Abs(candidate.FilePath)
+		if err != nil {
+			continue
+		}
+		missing = append(missing, MissingImport{
+			Name:      candidate.Name,
+			Specifier: w.importPaths.ImportSpecifier(fromFile, targetFile),
+		})
+	}
+	return missing, skipped
+}
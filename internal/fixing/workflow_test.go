@@ -0,0 +1,517 @@
+package fixing_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// mockAnalyzer returns the results of one pass per call, repeating the last
+// one
+type mockAnalyzer struct {
+	passes [][]*analysis.ModuleAnalysisResult
+	calls  int
+}
+
+func (m *mockAnalyzer) AnalyzeFile(filePath string) ([]*analysis.ModuleAnalysisResult, error) {
+	results := m.passes[min(m.calls, len(m.passes)-1)]
+	m.calls++
+	return results, nil
+}
+
+func (m *mockAnalyzer) AnalyzeDirectory(dirPath string) ([]*analysis.ModuleAnalysisResult, error) {
+	return m.AnalyzeFile(dirPath)
+}
+
+func TestWorkflow_FixPath_KeepsEncodingAndUndoes(t *testing.T) {
+	tempDir := t.TempDir()
+	journalDir := filepath.Join(tempDir, "journal")
+	testFile := filepath.Join(tempDir, "app.module.ts")
+
+	// A CRLF file with a byte order mark and restricted permissions
+	sourceCode := "\xEF\xBB\xBF" + strings.ReplaceAll(`import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+import { UsedModule } from "./used.module";
+
+@Module({
+  imports: [UnusedModule, UsedModule],
+})
+export class AppModule {}
+`, "\n", "\r\n")
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{{
+		{ModuleName: "AppModule", FilePath: testFile, UnusedImports: []string{"UnusedModule"}},
+	}}}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil).WithJournal(journalDir)
+
+	plan, err := workflow.Plan(testFile)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(plan.Files) != 1 {
+		t.Fatalf("Expected 1 planned file, got %d", len(plan.Files))
+	}
+	// The edits apply to the file as it is on disk
+	applied, err := fixing.ApplyEdits(plan.Files[0].Original, plan.Files[0].Edits)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != string(plan.Files[0].Fixed) {
+		t.Errorf("Expected the planned edits to produce the fixed file, got:\n%q", applied)
+	}
+
+	if _, err := workflow.FixPath(testFile); err != nil {
+		t.Fatalf("FixPath failed: %v", err)
+	}
+
+	expected := "\xEF\xBB\xBF" + strings.ReplaceAll(`import { Module } from "@nestjs/common";
+import { UsedModule } from "./used.module";
+
+@Module({
+  imports: [UsedModule],
+})
+export class AppModule {}
+`, "\n", "\r\n")
+	fixed, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read fixed file: %v", err)
+	}
+	if string(fixed) != expected {
+		t.Errorf("Result mismatch\nGot:\n%q\n\nExpected:\n%q", fixed, expected)
+	}
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Failed to stat fixed file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600 to be kept, got %v", info.Mode().Perm())
+	}
+
+	restored, err := fixing.UndoLastFix(journalDir)
+	if err != nil {
+		t.Fatalf("UndoLastFix failed: %v", err)
+	}
+	if len(restored) != 1 {
+		t.Errorf("Expected 1 restored file, got %v", restored)
+	}
+	original, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read restored file: %v", err)
+	}
+	if string(original) != sourceCode {
+		t.Errorf("Expected the original file to be restored, got:\n%q", original)
+	}
+
+	if _, err := fixing.UndoLastFix(journalDir); !errors.Is(err, fixing.ErrNoJournal) {
+		t.Errorf("Expected ErrNoJournal once the run is undone, got %v", err)
+	}
+}
+
+// pathAnalyzer returns the results of each path once, and nothing when a
+// path is analyzed again
+type pathAnalyzer struct {
+	results map[string][]*analysis.ModuleAnalysisResult
+}
+
+func (m *pathAnalyzer) AnalyzeFile(filePath string) ([]*analysis.ModuleAnalysisResult, error) {
+	results := m.results[filePath]
+	delete(m.results, filePath)
+	return results, nil
+}
+
+func (m *pathAnalyzer) AnalyzeDirectory(dirPath string) ([]*analysis.ModuleAnalysisResult, error) {
+	return m.AnalyzeFile(dirPath)
+}
+
+func TestWorkflow_FixPaths_UndoesAsOneRun(t *testing.T) {
+	tempDir := t.TempDir()
+	journalDir := filepath.Join(tempDir, "journal")
+
+	sourceCode := `import { UnusedModule } from "./unused.module";
+
+@Module({ imports: [UnusedModule] })
+export class %s {}
+`
+	analyzer := &pathAnalyzer{results: make(map[string][]*analysis.ModuleAnalysisResult)}
+	var paths []string
+	for _, name := range []string{"AppModule", "UsersModule"} {
+		path := filepath.Join(tempDir, strings.ToLower(name)+".ts")
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(sourceCode, name)), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		analyzer.results[path] = []*analysis.ModuleAnalysisResult{{ModuleName: name, FilePath: path, UnusedImports: []string{"UnusedModule"}}}
+		paths = append(paths, path)
+	}
+
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil).WithJournal(journalDir)
+	results, err := workflow.FixPaths(paths)
+	if err != nil {
+		t.Fatalf("FixPaths failed: %v", err)
+	}
+	if len(results) != 2 || !results[0].Fixed || !results[1].Fixed {
+		t.Fatalf("Expected both files to be fixed, got %+v", results)
+	}
+
+	restored, err := fixing.UndoLastFix(journalDir)
+	if err != nil {
+		t.Fatalf("UndoLastFix failed: %v", err)
+	}
+	if !reflect.DeepEqual(restored, paths) {
+		t.Errorf("Expected a single undo to restore %v, got %v", paths, restored)
+	}
+	if _, err := fixing.UndoLastFix(journalDir); !errors.Is(err, fixing.ErrNoJournal) {
+		t.Errorf("Expected ErrNoJournal once the run is undone, got %v", err)
+	}
+}
+
+func TestUndoLastFix_ChangedFile(t *testing.T) {
+	tempDir := t.TempDir()
+	journalDir := filepath.Join(tempDir, "journal")
+	testFile := filepath.Join(tempDir, "app.module.ts")
+
+	sourceCode := `import { UnusedModule } from "./unused.module";
+
+@Module({ imports: [UnusedModule] })
+export class AppModule {}
+`
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{{
+		{ModuleName: "AppModule", FilePath: testFile, UnusedImports: []string{"UnusedModule"}},
+	}}}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil).WithJournal(journalDir)
+	if _, err := workflow.FixPath(testFile); err != nil {
+		t.Fatalf("FixPath failed: %v", err)
+	}
+
+	// Edit the file after the fix
+	changed := "// edited\n"
+	if err := os.WriteFile(testFile, []byte(changed), 0644); err != nil {
+		t.Fatalf("Failed to edit test file: %v", err)
+	}
+
+	if _, err := fixing.UndoLastFix(journalDir); err == nil {
+		t.Error("Expected undo to refuse restoring a changed file")
+	}
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(content) != changed {
+		t.Errorf("Expected the changed file to be left alone, got:\n%s", content)
+	}
+}
+
+func TestSaveJournal_KeepsNewestJournals(t *testing.T) {
+	journalDir := filepath.Join(t.TempDir(), "journal")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 25 {
+		journal := &fixing.Journal{CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := fixing.SaveJournal(journalDir, journal); err != nil {
+			t.Fatalf("SaveJournal failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(journalDir)
+	if err != nil {
+		t.Fatalf("Failed to read journal directory: %v", err)
+	}
+	if len(entries) != 20 {
+		t.Fatalf("Expected 20 journals to be kept, got %d", len(entries))
+	}
+	// The oldest journals are the ones removed
+	oldest := fmt.Sprintf("%d.json", start.Add(5*time.Minute).UnixNano())
+	if entries[0].Name() != oldest {
+		t.Errorf("Expected the oldest kept journal to be %s, got %s", oldest, entries[0].Name())
+	}
+}
+
+func TestWorkflow_FixPath_RepeatsUntilStable(t *testing.T) {
+	tempDir := t.TempDir()
+	journalDir := filepath.Join(tempDir, "journal")
+	testFile := filepath.Join(tempDir, "app.module.ts")
+
+	sourceCode := `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+import { ExposedModule } from "./exposed.module";
+
+@Module({
+  imports: [UnusedModule, ExposedModule],
+})
+export class AppModule {}
+`
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// The second pass finds an import the first fix made unused
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{
+		{{ModuleName: "AppModule", FilePath: testFile, UnusedImports: []string{"UnusedModule"}}},
+		{{ModuleName: "AppModule", FilePath: testFile, UnusedImports: []string{"ExposedModule"}}},
+		{},
+	}}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil).WithJournal(journalDir)
+	results, err := workflow.FixPath(testFile)
+	if err != nil {
+		t.Fatalf("FixPath failed: %v", err)
+	}
+
+	// The result covers the changes of both passes
+	if len(results) != 1 || !results[0].Fixed {
+		t.Fatalf("Expected 1 fixed file, got %v", results)
+	}
+	expectedRemoved := []fixing.ElementChange{
+		{Module: "AppModule", Name: "UnusedModule", Kind: fixing.ElementImport},
+		{Module: "AppModule", Name: "ExposedModule", Kind: fixing.ElementImport},
+	}
+	if !reflect.DeepEqual(results[0].RemovedElements, expectedRemoved) {
+		t.Errorf("Expected removed elements %v, got %v", expectedRemoved, results[0].RemovedElements)
+	}
+	expectedStatements := []fixing.ImportChange{
+		{Action: fixing.ImportRemoved, Specifier: "./unused.module", Removed: []string{"UnusedModule"}},
+		{Action: fixing.ImportRemoved, Specifier: "./exposed.module", Removed: []string{"ExposedModule"}},
+	}
+	if !reflect.DeepEqual(results[0].ImportStatements, expectedStatements) {
+		t.Errorf("Expected import statement changes %v, got %v", expectedStatements, results[0].ImportStatements)
+	}
+
+	expected := `import { Module } from "@nestjs/common";
+
+@Module({
+  imports: [],
+})
+export class AppModule {}
+`
+	fixed, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read fixed file: %v", err)
+	}
+	if string(fixed) != expected {
+		t.Errorf("Result mismatch\nGot:\n%s\n\nExpected:\n%s", fixed, expected)
+	}
+	if analyzer.calls != 3 {
+		t.Errorf("Expected 3 analysis passes, got %d", analyzer.calls)
+	}
+	if results[0].Diff != fixing.UnifiedDiff(testFile, []byte(sourceCode), fixed) {
+		t.Errorf("Expected the diff to span every pass, got:\n%s", results[0].Diff)
+	}
+
+	// Undo reverts every pass
+	if _, err := fixing.UndoLastFix(journalDir); err != nil {
+		t.Fatalf("UndoLastFix failed: %v", err)
+	}
+	original, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read restored file: %v", err)
+	}
+	if string(original) != sourceCode {
+		t.Errorf("Expected the original file to be restored, got:\n%s", original)
+	}
+}
+
+// directoryAnalyzer returns the results of one pass per directory analysis,
+// like mockAnalyzer, and nothing for single files
+type directoryAnalyzer struct {
+	mockAnalyzer
+}
+
+func (m *directoryAnalyzer) AnalyzeFile(filePath string) ([]*analysis.ModuleAnalysisResult, error) {
+	return nil, nil
+}
+
+func (m *directoryAnalyzer) AnalyzeDirectory(dirPath string) ([]*analysis.ModuleAnalysisResult, error) {
+	return m.mockAnalyzer.AnalyzeFile(dirPath)
+}
+
+func TestWorkflow_FixPath_ReanalyzesImporters(t *testing.T) {
+	tempDir := t.TempDir()
+	sourceCode := `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+
+@Module({
+  imports: [UnusedModule],
+})
+export class %s {}
+`
+	usersFile := filepath.Join(tempDir, "users.module.ts")
+	appFile := filepath.Join(tempDir, "app.module.ts")
+	for file, module := range map[string]string{usersFile: "UsersModule", appFile: "AppModule"} {
+		if err := os.WriteFile(file, []byte(fmt.Sprintf(sourceCode, module)), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// Fixing UsersModule leaves AppModule, which imports it, with an unused
+	// import, while UsersModule's own file has nothing left to fix
+	analyzer := &directoryAnalyzer{mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{
+		{{ModuleName: "UsersModule", FilePath: usersFile, UnusedImports: []string{"UnusedModule"}}},
+		{{ModuleName: "AppModule", FilePath: appFile, UnusedImports: []string{"UnusedModule"}}},
+		{},
+	}}}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil)
+	results, err := workflow.FixPath(tempDir)
+	if err != nil {
+		t.Fatalf("FixPath failed: %v", err)
+	}
+
+	if len(results) != 2 || !results[0].Fixed || !results[1].Fixed {
+		t.Fatalf("Expected both modules to be fixed, got %+v", results)
+	}
+	if results[0].File != appFile || results[1].File != usersFile {
+		t.Errorf("Expected results for %s and %s, got %+v", appFile, usersFile, results)
+	}
+	if analyzer.calls != 3 {
+		t.Errorf("Expected 3 analysis passes, got %d", analyzer.calls)
+	}
+}
+
+func TestWorkflow_FixPath_WriteGuard(t *testing.T) {
+	tempDir := t.TempDir()
+	sourceCode := `import { Module } from "@nestjs/common";
+import { UnusedModule } from "./unused.module";
+
+@Module({
+  imports: [UnusedModule],
+})
+export class %s {}
+`
+	cleanFile := filepath.Join(tempDir, "clean.module.ts")
+	dirtyFile := filepath.Join(tempDir, "dirty.module.ts")
+	for file, module := range map[string]string{cleanFile: "CleanModule", dirtyFile: "DirtyModule"} {
+		if err := os.WriteFile(file, []byte(fmt.Sprintf(sourceCode, module)), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{
+		{
+			{ModuleName: "CleanModule", FilePath: cleanFile, UnusedImports: []string{"UnusedModule"}},
+			{ModuleName: "DirtyModule", FilePath: dirtyFile, UnusedImports: []string{"UnusedModule"}},
+		},
+		{},
+	}}
+	var checked []string
+	guard := func(paths []string) (map[string]string, error) {
+		checked = append(checked, paths...)
+		return map[string]string{dirtyFile: "has uncommitted changes"}, nil
+	}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil).WithWriteGuard(guard)
+	results, err := workflow.FixPath(tempDir)
+	if err != nil {
+		t.Fatalf("FixPath failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %v", results)
+	}
+	if !results[0].Fixed || results[0].File != cleanFile {
+		t.Errorf("Expected %s to be fixed, got %+v", cleanFile, results[0])
+	}
+	if results[1].Fixed || results[1].Error != "has uncommitted changes" {
+		t.Errorf("Expected %s to be refused, got %+v", dirtyFile, results[1])
+	}
+	dirty, err := os.ReadFile(dirtyFile)
+	if err != nil {
+		t.Fatalf("Failed to read refused file: %v", err)
+	}
+	if string(dirty) != fmt.Sprintf(sourceCode, "DirtyModule") {
+		t.Errorf("Expected the refused file to be unchanged, got:\n%s", dirty)
+	}
+	if !reflect.DeepEqual(checked, []string{cleanFile, dirtyFile}) {
+		t.Errorf("Expected each file to be checked once, got %v", checked)
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	results := []*fixing.FixResult{
+		{
+			File:            "src/app.module.ts",
+			Fixed:           true,
+			RemovedElements: []fixing.ElementChange{{Module: "AppModule", Name: "UnusedModule", Kind: fixing.ElementImport}},
+		},
+		{File: "src/other.module.ts", Error: "has uncommitted changes"},
+	}
+	expected := `Remove unused NestJS module imports
+
+Generated by nestjs-module-lint --fix.
+
+src/app.module.ts:
+- remove UnusedModule from AppModule
+`
+	if message := fixing.CommitMessage(results); message != expected {
+		t.Errorf("Message mismatch\nGot:\n%s\n\nExpected:\n%s", message, expected)
+	}
+
+	results[0].AddedElements = []fixing.ElementChange{{Module: "AppModule", Name: "UsersModule", Kind: fixing.ElementImport}}
+	if message := fixing.CommitMessage(results); !strings.HasPrefix(message, "Fix NestJS module imports\n") || !strings.Contains(message, "- add UsersModule to AppModule\n") {
+		t.Errorf("Expected a message covering added imports, got:\n%s", message)
+	}
+}
+
+func TestWorkflow_Plan_RollsBackInvalidFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "app.module.ts")
+
+	sourceCode := `import { UnusedModule } from "./unused.module";
+
+@Module({ imports: [UnusedModule }
+export class AppModule {`
+	if err := os.WriteFile(testFile, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	analyzer := &mockAnalyzer{passes: [][]*analysis.ModuleAnalysisResult{{
+		{ModuleName: "AppModule", FilePath: testFile, UnusedImports: []string{"UnusedModule"}},
+	}}}
+	workflow := fixing.NewWorkflow(analyzer, fixing.NewFixer(typescript.GetLanguage()), nil)
+
+	results, err := workflow.FixPath(testFile)
+	if err != nil {
+		t.Fatalf("FixPath failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Fixed || results[0].Error == "" {
+		t.Errorf("Expected the file to be reported as not fixed, got %v", results)
+	}
+
+	plan, err := workflow.Plan(testFile)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("Expected no changes, got %d files", len(plan.Files))
+	}
+	if len(plan.RolledBack) != 1 || plan.RolledBack[0].Path != testFile {
+		t.Errorf("Expected the file to be rolled back, got %v", plan.RolledBack)
+	}
+}
+
+func TestFixer_VerifySyntax(t *testing.T) {
+	fixer := fixing.NewFixer(typescript.GetLanguage())
+
+	valid := []byte("@Module({ imports: [A] })\nexport class AppModule {}\n")
+	broken := []byte("@Module({ imports: [A }\nexport class AppModule {}\n")
+
+	if err := fixer.VerifySyntax(valid, valid); err != nil {
+		t.Errorf("Expected a valid fix to pass, got %v", err)
+	}
+	if err := fixer.VerifySyntax(valid, broken); err == nil {
+		t.Error("Expected a fix introducing syntax errors to be rejected")
+	}
+	if err := fixer.VerifySyntax(broken, broken); err != nil {
+		t.Errorf("Expected existing syntax errors to be tolerated, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotRepository is returned when a path is not inside a git work tree, or
+// no git binary is installed
+var ErrNotRepository = errors.New("not inside a git work tree")
+
+// Repository is a git work tree, driven through the local git binary
+type Repository struct {
+	root string
+}
+
+// Open finds the git work tree holding a file or directory. It returns
+// ErrNotRepository when there is none, and the error of git when git fails
+// for another reason.
+func Open(path string) (*Repository, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrNotRepository
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := absPath
+	if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+		dir = filepath.Dir(absPath)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	// Untranslated messages tell a missing repository from other failures
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if strings.Contains(message, "not a git repository") {
+			return nil, ErrNotRepository
+		}
+		// Other failures, e.g. a repository with dubious ownership, must not
+		// pass for a missing repository
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("git rev-parse failed in %s: %s", dir, message)
+	}
+	root := strings.TrimSpace(stdout.String())
+	// Resolve symlinks the same way git does, e.g. /tmp on macOS
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	return &Repository{root: root}, nil
+}
+
+// Root returns the absolute path of the work tree
+func (r *Repository) Root() string {
+	return r.root
+}
+
+// DirtyFiles returns the files among paths that have uncommitted changes,
+// staged or not, or are untracked
+func (r *Repository) DirtyFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	relPaths, err := r.relativePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	pathsByRelPath := make(map[string]string)
+	for i, relPath := range relPaths {
+		pathsByRelPath[relPath] = paths[i]
+	}
+
+	output, err := r.run(append([]string{"status", "--porcelain=v1", "-z", "--untracked-files=all", "--"}, relPaths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirty []string
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// Renames and copies are followed by their source path
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		if path, ok := pathsByRelPath[entry[3:]]; ok {
+			dirty = append(dirty, path)
+		}
+	}
+	sort.Strings(dirty)
+	return dirty, nil
+}
+
+// Commit commits the current content of paths, leaving any other staged
+// change out of the commit
+func (r *Repository) Commit(message string, paths []string) error {
+	relPaths, err := r.relativePaths(paths)
+	if err != nil {
+		return err
+	}
+	if _, err := r.run(append([]string{"add", "--"}, relPaths...)...); err != nil {
+		return err
+	}
+	_, err = r.run(append([]string{"commit", "--quiet", "-m", message, "--"}, relPaths...)...)
+	return err
+}
+
+// relativePaths converts paths to slash separated paths relative to the work
+// tree root
+func (r *Repository) relativePaths(paths []string) ([]string, error) {
+	relPaths := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+			absPath = resolved
+		}
+		relPath, err := filepath.Rel(r.root, absPath)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside of the git work tree %s", path, r.root)
+		}
+		relPaths = append(relPaths, filepath.ToSlash(relPath))
+	}
+	return relPaths, nil
+}
+
+// run runs a git command in the work tree root and returns its output
+func (r *Repository) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.root
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], message)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,122 @@
+package git_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/git"
+)
+
+// newRepository creates a git work tree with one committed file
+func newRepository(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		runGit(t, dir, args...)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.module.ts"), []byte("original\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "--quiet", "-m", "initial")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+func TestOpen_NotRepository(t *testing.T) {
+	if _, err := git.Open(t.TempDir()); !errors.Is(err, git.ErrNotRepository) {
+		t.Errorf("Expected ErrNotRepository, got %v", err)
+	}
+}
+
+func TestOpen_GitError(t *testing.T) {
+	dir := newRepository(t)
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte("[broken\n"), 0644); err != nil {
+		t.Fatalf("Failed to break the git config: %v", err)
+	}
+
+	// A repository git cannot read is not mistaken for no repository
+	_, err := git.Open(dir)
+	if err == nil || errors.Is(err, git.ErrNotRepository) {
+		t.Fatalf("Expected the git error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "config") {
+		t.Errorf("Expected the error to tell what git failed on, got %v", err)
+	}
+}
+
+func TestRepository_DirtyFiles(t *testing.T) {
+	dir := newRepository(t)
+	cleanFile := filepath.Join(dir, "app.module.ts")
+	dirtyFile := filepath.Join(dir, "users.module.ts")
+	if err := os.WriteFile(dirtyFile, []byte("untracked\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	repo, err := git.Open(cleanFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	dirty, err := repo.DirtyFiles([]string{cleanFile, dirtyFile})
+	if err != nil {
+		t.Fatalf("DirtyFiles failed: %v", err)
+	}
+	if !reflect.DeepEqual(dirty, []string{dirtyFile}) {
+		t.Errorf("Expected only %s to be dirty, got %v", dirtyFile, dirty)
+	}
+}
+
+func TestRepository_Commit(t *testing.T) {
+	dir := newRepository(t)
+	file := filepath.Join(dir, "app.module.ts")
+	other := filepath.Join(dir, "other.ts")
+	if err := os.WriteFile(file, []byte("fixed\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit test file: %v", err)
+	}
+	// A staged change to another file stays out of the commit
+	if err := os.WriteFile(other, []byte("staged\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	runGit(t, dir, "add", "other.ts")
+
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := repo.Commit("Remove unused imports", []string{file}); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	committed := runGit(t, dir, "show", "--name-only", "--format=%s", "HEAD")
+	if !strings.Contains(committed, "Remove unused imports") || !strings.Contains(committed, "app.module.ts") || strings.Contains(committed, "other.ts") {
+		t.Errorf("Expected a commit of app.module.ts only, got:\n%s", committed)
+	}
+	if status := runGit(t, dir, "status", "--porcelain"); !strings.Contains(status, "A  other.ts") {
+		t.Errorf("Expected other.ts to stay staged, got:\n%s", status)
+	}
+}
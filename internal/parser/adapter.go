@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"bytes"
 	"context"
 	"os"
+	"sync"
 
 	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
 	sitter "github.com/smacker/go-tree-sitter"
@@ -11,45 +13,90 @@ import (
 // ParserAdapter adapts the existing parser functions to implement the ModuleParser interface
 type ParserAdapter struct {
 	lang *sitter.Language
+	// moduleDecorators lists custom decorators that wrap @Module() metadata
+	moduleDecorators []string
+
+	mu sync.Mutex
+	// files holds the last parsed content of each file, so the queries run
+	// for one file share a single parse
+	files map[string]*parsedFile
+}
+
+// parsedFile is the syntax tree of a file's content
+type parsedFile struct {
+	sourceCode []byte
+	tree       *sitter.Tree
 }
 
 // NewParserAdapter creates a new parser adapter
-func NewParserAdapter(lang *sitter.Language) *ParserAdapter {
+func NewParserAdapter(lang *sitter.Language, moduleDecorators ...string) *ParserAdapter {
 	return &ParserAdapter{
-		lang: lang,
+		lang:             lang,
+		moduleDecorators: moduleDecorators,
+		files:            make(map[string]*parsedFile),
 	}
 }
 
-// ParseModuleInfo implements the ModuleParser interface
-func (p *ParserAdapter) ParseModuleInfo(filePath string) (*analysis.ModuleInfo, error) {
+// parse returns the syntax tree of a file, parsing it again only when its
+// content changed since the last call. Each call gets its own copy of the
+// tree, since a tree caches its nodes and cannot be shared between goroutines.
+func (p *ParserAdapter) parse(filePath string) (*sitter.Node, []byte, error) {
 	sourceCode, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	tree, err := sitter.ParseCtx(context.Background(), sourceCode, p.lang)
+	p.mu.Lock()
+	file, ok := p.files[filePath]
+	p.mu.Unlock()
+	if !ok || !bytes.Equal(file.sourceCode, sourceCode) {
+		parser := sitter.NewParser()
+		parser.SetLanguage(p.lang)
+		tree, err := parser.ParseCtx(context.Background(), nil, sourceCode)
+		if err != nil {
+			return nil, nil, err
+		}
+		file = &parsedFile{sourceCode: sourceCode, tree: tree}
+		p.mu.Lock()
+		p.files[filePath] = file
+		p.mu.Unlock()
+	}
+	return file.tree.Copy().RootNode(), file.sourceCode, nil
+}
+
+// ParseModuleInfo implements the ModuleParser interface
+func (p *ParserAdapter) ParseModuleInfo(filePath string) (*analysis.ModuleInfo, error) {
+	tree, sourceCode, err := p.parse(filePath)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get imports by module
-	importsByModule, err := ParseModuleImports(tree, sourceCode)
+	importsByModule, err := ParseModuleImports(tree, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get exports by module
-	exportsByModule, err := ParseModuleExports(tree, sourceCode)
+	exportsByModule, err := ParseModuleExports(tree, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get providers by module
-	providersByModule, err := ParseModuleProviders(tree, sourceCode)
+	providersByModule, err := ParseModuleProviders(tree, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Merge in metadata returned from static DynamicModule factories
+	dynamicModules, err := ParseDynamicModules(tree, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	exportsByModule = MergeDynamicModuleExports(exportsByModule, dynamicModules)
+	providersByModule = MergeDynamicModuleProviders(providersByModule, dynamicModules)
+
 	// For simplicity, take the first module found
 	// TODO: This could be improved to handle multiple modules per file
 	for moduleName := range importsByModule {
@@ -68,60 +115,178 @@ func (p *ParserAdapter) ParseModuleInfo(filePath string) (*analysis.ModuleInfo,
 
 // GetImportsByModule implements the ModuleParser interface
 func (p *ParserAdapter) GetImportsByModule(filePath string) (map[string][]string, error) {
-	sourceCode, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	tree, err := sitter.ParseCtx(context.Background(), sourceCode, p.lang)
+	tree, sourceCode, err := p.parse(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	return ParseModuleImports(tree, sourceCode)
+	return ParseModuleImports(tree, sourceCode, p.moduleDecorators...)
 }
 
 // GetExportsByModule implements the ModuleParser interface
 func (p *ParserAdapter) GetExportsByModule(filePath string) (map[string][]string, error) {
-	sourceCode, err := os.ReadFile(filePath)
+	tree, sourceCode, err := p.parse(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	tree, err := sitter.ParseCtx(context.Background(), sourceCode, p.lang)
+	exportsByModule, err := ParseModuleExports(tree, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
-	return ParseModuleExports(tree, sourceCode)
+	dynamicModules, err := ParseDynamicModules(tree, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	return MergeDynamicModuleExports(exportsByModule, dynamicModules), nil
 }
 
 // GetProvidersByModule implements the ModuleParser interface
 func (p *ParserAdapter) GetProvidersByModule(filePath string) (map[string][]string, error) {
-	sourceCode, err := os.ReadFile(filePath)
+	tree, sourceCode, err := p.parse(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	tree, err := sitter.ParseCtx(context.Background(), sourceCode, p.lang)
+	providersByModule, err := ParseModuleProviders(tree, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
-	return ParseModuleProviders(tree, sourceCode)
+	dynamicModules, err := ParseDynamicModules(tree, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	return MergeDynamicModuleProviders(providersByModule, dynamicModules), nil
 }
 
 // GetImportPaths implements the ModuleParser interface
 func (p *ParserAdapter) GetImportPaths(filePath string) (map[string]string, error) {
-	sourceCode, err := os.ReadFile(filePath)
+	tree, sourceCode, err := p.parse(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	tree, err := sitter.ParseCtx(context.Background(), sourceCode, p.lang)
+	return ParseImportPaths(tree, sourceCode)
+}
+
+// GetProviderInjectionsByModule implements the ModuleParser interface
+func (p *ParserAdapter) GetProviderInjectionsByModule(filePath string) (map[string][]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	return ParseImportPaths(tree, sourceCode)
+	return ParseModuleProviderInjections(tree, sourceCode, p.moduleDecorators...)
+}
+
+// GetInjectedTokens implements the ModuleParser interface
+func (p *ParserAdapter) GetInjectedTokens(filePath string) ([]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseInjectedTokens(tree, sourceCode)
+}
+
+// GetTokenDeclarations implements the ModuleParser interface
+func (p *ParserAdapter) GetTokenDeclarations(filePath string) (map[string]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseTokenDeclarations(tree, sourceCode)
+}
+
+// GetReExports implements the ModuleParser interface
+func (p *ParserAdapter) GetReExports(filePath string) (map[string][]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseReExports(tree, sourceCode)
+}
+
+// GetFeatureEntitiesByModule implements the ModuleParser interface
+func (p *ParserAdapter) GetFeatureEntitiesByModule(filePath string) (map[string][]analysis.FeatureEntity, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFeatureEntities(tree, sourceCode, p.moduleDecorators...)
+}
+
+// GetInjectedModels implements the ModuleParser interface
+func (p *ParserAdapter) GetInjectedModels(filePath string) ([]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseInjectedModels(tree, sourceCode)
+}
+
+// GetQueueRegistrationsByModule implements the ModuleParser interface
+func (p *ParserAdapter) GetQueueRegistrationsByModule(filePath string) (map[string][]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseQueueRegistrations(tree, sourceCode, p.moduleDecorators...)
+}
+
+// GetQueueUsages implements the ModuleParser interface
+func (p *ParserAdapter) GetQueueUsages(filePath string) ([]analysis.QueueUsage, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseQueueUsages(tree, sourceCode)
+}
+
+// GetImportReferencesByModule implements the ModuleParser interface
+func (p *ParserAdapter) GetImportReferencesByModule(filePath string) (map[string][]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseImportReferences(tree, sourceCode, p.moduleDecorators...)
+}
+
+// GetGlobalModules implements the ModuleParser interface
+func (p *ParserAdapter) GetGlobalModules(filePath string) ([]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseGlobalModules(tree, sourceCode, p.moduleDecorators...)
+}
+
+// GetConstructorDependencies implements the ModuleParser interface
+func (p *ParserAdapter) GetConstructorDependencies(filePath string) ([]string, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseConstructorDependencies(tree, sourceCode)
+}
+
+// GetModuleLocations implements the ModuleParser interface
+func (p *ParserAdapter) GetModuleLocations(filePath string) (map[string]analysis.ModuleLocations, error) {
+	tree, sourceCode, err := p.parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseModuleLocations(tree, sourceCode, p.moduleDecorators...)
 }
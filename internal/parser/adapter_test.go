@@ -0,0 +1,94 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParserAdapter_ReparsesChangedFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.module.ts")
+	writeModule := func(imports string) {
+		t.Helper()
+		sourceCode := `
+import { Module } from "@nestjs/common";
+@Module({
+  imports: [` + imports + `],
+})
+export class AppModule {}
+`
+		if err := os.WriteFile(file, []byte(sourceCode), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	adapter := parser.NewParserAdapter(typescript.GetLanguage())
+
+	writeModule("UsersModule, OrdersModule")
+	imports, err := adapter.GetImportsByModule(file)
+	if err != nil {
+		t.Fatalf("GetImportsByModule failed: %v", err)
+	}
+	expected := map[string][]string{"AppModule": {"UsersModule", "OrdersModule"}}
+	if !reflect.DeepEqual(imports, expected) {
+		t.Errorf("Expected %v, got %v", expected, imports)
+	}
+
+	// A query of the unchanged file reuses the parse
+	providers, err := adapter.GetProvidersByModule(file)
+	if err != nil {
+		t.Fatalf("GetProvidersByModule failed: %v", err)
+	}
+	if len(providers["AppModule"]) != 0 {
+		t.Errorf("Expected no providers, got %v", providers)
+	}
+
+	// Changed content is parsed again
+	writeModule("UsersModule")
+	imports, err = adapter.GetImportsByModule(file)
+	if err != nil {
+		t.Fatalf("GetImportsByModule failed: %v", err)
+	}
+	expected = map[string][]string{"AppModule": {"UsersModule"}}
+	if !reflect.DeepEqual(imports, expected) {
+		t.Errorf("Expected %v after the change, got %v", expected, imports)
+	}
+}
+
+func TestParserAdapter_ConcurrentQueries(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.module.ts")
+	sourceCode := `
+import { Module } from "@nestjs/common";
+@Module({
+  imports: [UsersModule],
+  exports: [UsersModule],
+})
+export class AppModule {}
+`
+	if err := os.WriteFile(file, []byte(sourceCode), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	adapter := parser.NewParserAdapter(typescript.GetLanguage())
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exports, err := adapter.GetExportsByModule(file)
+			if err != nil {
+				t.Errorf("GetExportsByModule failed: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(exports["AppModule"], []string{"UsersModule"}) {
+				t.Errorf("Expected AppModule to export UsersModule, got %v", exports)
+			}
+		}()
+	}
+	wg.Wait()
+}
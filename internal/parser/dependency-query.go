@@ -0,0 +1,76 @@
+package parser
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ParseImportReferences returns every module referenced in each module's
+// imports, including dynamic module calls and forwardRef() targets, grouped
+// by module name
+func ParseImportReferences(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) (map[string][]string, error) {
+	return parseModuleLists(node, sourceCode, []string{"imports"}, customDecorators, func(declaration *ModuleDeclaration, _ string) []string {
+		return declaration.ImportedModuleNames()
+	})
+}
+
+// ParseGlobalModules returns the names of the modules decorated with @Global()
+func ParseGlobalModules(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) ([]string, error) {
+	declarations, err := ParseModuleDeclarations(node, sourceCode, customDecorators...)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, declaration := range declarations {
+		if declaration.IsGlobal() {
+			names = append(names, declaration.Name)
+		}
+	}
+	return names, nil
+}
+
+// ParseConstructorDependencies returns the class types injected through
+// constructor parameters, e.g. UsersService in
+// constructor(private readonly users: UsersService). Parameters with
+// decorators such as @Inject() are injected by token and skipped.
+func ParseConstructorDependencies(
+	node *sitter.Node,
+	sourceCode []byte,
+) ([]string, error) {
+	var dependencies []string
+	walkNodes(node, func(n *sitter.Node) bool {
+		if n.Type() != "method_definition" {
+			return true
+		}
+		name := n.ChildByFieldName("name")
+		parameters := n.ChildByFieldName("parameters")
+		if name == nil || parameters == nil || name.Content(sourceCode) != "constructor" {
+			return false
+		}
+		for i := 0; i < int(parameters.NamedChildCount()); i++ {
+			parameter := parameters.NamedChild(i)
+			if parameter.Type() != "required_parameter" && parameter.Type() != "optional_parameter" {
+				continue
+			}
+			if parameter.ChildByFieldName("decorator") != nil {
+				continue
+			}
+			annotation := parameter.ChildByFieldName("type")
+			if annotation == nil || annotation.NamedChildCount() == 0 {
+				continue
+			}
+			if dependency := annotation.NamedChild(0); dependency.Type() == "type_identifier" {
+				dependencies = append(dependencies, dependency.Content(sourceCode))
+			}
+		}
+		return false
+	})
+	return dependencies, nil
+}
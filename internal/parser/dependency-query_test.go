@@ -0,0 +1,81 @@
+package parser_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseImportReferences(t *testing.T) {
+	sourceCode := `
+import { Global, Module, forwardRef } from "@nestjs/common";
+
+@Global()
+@Module({
+  imports: [UsersModule, ConfigModule.forRoot(), forwardRef(() => OrdersModule)],
+})
+export class AppModule {}
+
+@Module({ providers: [LoggerService] })
+export class LoggerModule {}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	references, err := parser.ParseImportReferences(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get import references: %v", err)
+	}
+	expected := []string{"UsersModule", "ConfigModule", "OrdersModule"}
+	if !reflect.DeepEqual(references["AppModule"], expected) {
+		t.Errorf("Expected references %v, got %v", expected, references["AppModule"])
+	}
+
+	globals, err := parser.ParseGlobalModules(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get global modules: %v", err)
+	}
+	if !reflect.DeepEqual(globals, []string{"AppModule"}) {
+		t.Errorf("Expected only AppModule to be global, got %v", globals)
+	}
+}
+
+func TestParseConstructorDependencies(t *testing.T) {
+	sourceCode := `
+import { Inject, Injectable } from "@nestjs/common";
+
+@Injectable()
+export class OrdersService {
+  constructor(
+    private readonly users: UsersService,
+    @Inject(CONFIG) private readonly config: Config,
+    private logger?: Logger,
+    private readonly names: string[],
+  ) {}
+
+  find(users: IgnoredService) {}
+}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	dependencies, err := parser.ParseConstructorDependencies(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get constructor dependencies: %v", err)
+	}
+
+	expected := []string{"UsersService", "Logger"}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("Expected dependencies %v, got %v", expected, dependencies)
+	}
+}
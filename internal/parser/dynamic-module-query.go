@@ -0,0 +1,265 @@
+package parser
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// These are defined by the order of the captures in the query, if the query is
+// changed this will need to be updated.
+const (
+	dynamicModuleNameIndex = uint32(0)
+	dynamicMethodBodyIndex = uint32(2)
+)
+
+// configurableModuleClassName is the base class generated by NestJS'
+// ConfigurableModuleBuilder
+const configurableModuleClassName = "ConfigurableModuleClass"
+
+// DynamicModuleMetadata holds the metadata a module class returns from its
+// static DynamicModule factories (forRoot, register, forFeature, ...)
+type DynamicModuleMetadata struct {
+	Providers []string
+	Exports   []string
+}
+
+// ParseDynamicModules finds static methods on classes that return DynamicModule
+// object literals and collects their providers and exports by module name.
+// A static method is treated as a DynamicModule factory when it is annotated
+// as returning DynamicModule, when the returned object has a `module` key, or
+// when the class extends a ConfigurableModuleClass.
+func ParseDynamicModules(
+	node *sitter.Node,
+	sourceCode []byte,
+) (map[string]*DynamicModuleMetadata, error) {
+	dynamicModuleQuery, err := LoadDynamicModuleQuery()
+	if err != nil {
+		return nil, err
+	}
+	configurableBases := configurableModuleBaseNames(node, sourceCode)
+
+	qc := sitter.NewQueryCursor()
+	qc.Exec(dynamicModuleQuery, node)
+	dynamicModules := make(map[string]*DynamicModuleMetadata)
+	for {
+		m, ok := qc.NextMatch()
+		if !ok {
+			break
+		}
+		var moduleNameNode, methodBody *sitter.Node
+		for _, c := range m.Captures {
+			if c.Index == dynamicModuleNameIndex {
+				moduleNameNode = c.Node
+			} else if c.Index == dynamicMethodBodyIndex {
+				methodBody = c.Node
+			}
+		}
+		if moduleNameNode == nil || methodBody == nil {
+			continue
+		}
+		moduleName := moduleNameNode.Content(sourceCode)
+		extendsConfigurable := configurableBases[classBaseName(moduleNameNode.Parent(), sourceCode)]
+		returnsDynamicModule := returnTypeIsDynamicModule(methodBody.Parent(), sourceCode)
+
+		for _, returned := range returnedObjects(methodBody) {
+			if !extendsConfigurable && !returnsDynamicModule && objectPair(returned, sourceCode, "module") == nil {
+				continue
+			}
+			metadata, ok := dynamicModules[moduleName]
+			if !ok {
+				metadata = &DynamicModuleMetadata{}
+				dynamicModules[moduleName] = metadata
+			}
+			metadata.Providers = append(metadata.Providers, objectArrayIdentifiers(returned, sourceCode, "providers")...)
+			metadata.Exports = append(metadata.Exports, objectArrayIdentifiers(returned, sourceCode, "exports")...)
+		}
+	}
+	return dynamicModules, nil
+}
+
+// MergeDynamicModuleProviders adds the providers declared by dynamic module
+// factories to the providers found in @Module() decorators
+func MergeDynamicModuleProviders(
+	providersByModule map[string][]string,
+	dynamicModules map[string]*DynamicModuleMetadata,
+) map[string][]string {
+	for moduleName, metadata := range dynamicModules {
+		providersByModule[moduleName] = appendUnique(providersByModule[moduleName], metadata.Providers...)
+	}
+	return providersByModule
+}
+
+// MergeDynamicModuleExports adds the exports declared by dynamic module
+// factories to the exports found in @Module() decorators
+func MergeDynamicModuleExports(
+	exportsByModule map[string][]string,
+	dynamicModules map[string]*DynamicModuleMetadata,
+) map[string][]string {
+	for moduleName, metadata := range dynamicModules {
+		exportsByModule[moduleName] = appendUnique(exportsByModule[moduleName], metadata.Exports...)
+	}
+	return exportsByModule
+}
+
+// appendUnique appends values that are not already present in list
+func appendUnique(list []string, values ...string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		seen[v] = true
+	}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// configurableModuleBaseNames returns the local names bound to a
+// ConfigurableModuleClass, including aliases like
+// const { ConfigurableModuleClass: BaseModule } = new ConfigurableModuleBuilder().build();
+// import { ConfigurableModuleClass as BaseModule } from './x.module-definition';
+func configurableModuleBaseNames(node *sitter.Node, sourceCode []byte) map[string]bool {
+	names := map[string]bool{configurableModuleClassName: true}
+	walkNodes(node, func(n *sitter.Node) bool {
+		switch n.Type() {
+		case "pair_pattern":
+			key := n.ChildByFieldName("key")
+			value := n.ChildByFieldName("value")
+			if key != nil && value != nil && value.Type() == "identifier" &&
+				key.Content(sourceCode) == configurableModuleClassName {
+				names[value.Content(sourceCode)] = true
+			}
+		case "import_specifier":
+			name := n.ChildByFieldName("name")
+			alias := n.ChildByFieldName("alias")
+			if name != nil && alias != nil && name.Content(sourceCode) == configurableModuleClassName {
+				names[alias.Content(sourceCode)] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// classBaseName returns the identifier a class declaration extends, if any
+func classBaseName(classDecl *sitter.Node, sourceCode []byte) string {
+	if classDecl == nil {
+		return ""
+	}
+	for i := 0; i < int(classDecl.NamedChildCount()); i++ {
+		heritage := classDecl.NamedChild(i)
+		if heritage.Type() != "class_heritage" {
+			continue
+		}
+		for j := 0; j < int(heritage.NamedChildCount()); j++ {
+			clause := heritage.NamedChild(j)
+			if clause.Type() != "extends_clause" {
+				continue
+			}
+			if value := clause.ChildByFieldName("value"); value != nil {
+				return value.Content(sourceCode)
+			}
+		}
+	}
+	return ""
+}
+
+// returnTypeIsDynamicModule checks for a DynamicModule or Promise<DynamicModule> return type annotation
+func returnTypeIsDynamicModule(method *sitter.Node, sourceCode []byte) bool {
+	if method == nil {
+		return false
+	}
+	returnType := method.ChildByFieldName("return_type")
+	if returnType == nil {
+		return false
+	}
+	return strings.Contains(returnType.Content(sourceCode), "DynamicModule")
+}
+
+// returnedObjects finds the object literals returned from a function body,
+// without descending into nested functions or classes
+func returnedObjects(body *sitter.Node) []*sitter.Node {
+	var objects []*sitter.Node
+	walkNodes(body, func(n *sitter.Node) bool {
+		switch n.Type() {
+		case "function_declaration", "function_expression", "arrow_function", "class_declaration", "class":
+			return false
+		case "return_statement":
+			if n.NamedChildCount() > 0 {
+				if obj := unwrapExpression(n.NamedChild(0)); obj != nil && obj.Type() == "object" {
+					objects = append(objects, obj)
+				}
+			}
+			return false
+		}
+		return true
+	})
+	return objects
+}
+
+// unwrapExpression strips parentheses, type assertions and awaits from an expression
+func unwrapExpression(n *sitter.Node) *sitter.Node {
+	for n != nil {
+		switch n.Type() {
+		case "parenthesized_expression", "as_expression", "satisfies_expression", "await_expression", "non_null_expression":
+			if n.NamedChildCount() == 0 {
+				return n
+			}
+			n = n.NamedChild(0)
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// objectPair returns the value node for the given key of an object literal
+func objectPair(object *sitter.Node, sourceCode []byte, key string) *sitter.Node {
+	for i := 0; i < int(object.NamedChildCount()); i++ {
+		child := object.NamedChild(i)
+		switch child.Type() {
+		case "pair":
+			keyNode := child.ChildByFieldName("key")
+			if keyNode != nil && strings.Trim(keyNode.Content(sourceCode), `'"`) == key {
+				return child.ChildByFieldName("value")
+			}
+		case "shorthand_property_identifier":
+			if child.Content(sourceCode) == key {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// objectArrayIdentifiers returns the identifiers listed in the array stored under key
+func objectArrayIdentifiers(object *sitter.Node, sourceCode []byte, key string) []string {
+	value := objectPair(object, sourceCode, key)
+	if value == nil || value.Type() != "array" {
+		return nil
+	}
+	var names []string
+	for i := 0; i < int(value.NamedChildCount()); i++ {
+		element := value.NamedChild(i)
+		if element.Type() == "identifier" {
+			names = append(names, element.Content(sourceCode))
+		}
+	}
+	return names
+}
+
+// walkNodes visits every named node depth first; visit returns false to skip children
+func walkNodes(node *sitter.Node, visit func(*sitter.Node) bool) {
+	if node == nil || !visit(node) {
+		return
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		walkNodes(node.NamedChild(i), visit)
+	}
+}
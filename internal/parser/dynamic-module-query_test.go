@@ -0,0 +1,117 @@
+package parser_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseDynamicModules(t *testing.T) {
+	tests := []struct {
+		name              string
+		sourceCode        string
+		expectedExports   map[string][]string
+		expectedProviders map[string][]string
+	}{
+		{
+			name: "static forRoot returning DynamicModule",
+			sourceCode: `
+import { DynamicModule, Module } from "@nestjs/common";
+import { MailerService } from "./mailer.service";
+@Module({})
+export class MailerModule {
+  static forRoot(options: MailerOptions): DynamicModule {
+    return {
+      module: MailerModule,
+      providers: [MailerService, { provide: OPTIONS, useValue: options }],
+      exports: [MailerService],
+    };
+  }
+}
+`,
+			expectedExports:   map[string][]string{"MailerModule": {"MailerService"}},
+			expectedProviders: map[string][]string{"MailerModule": {"MailerService"}},
+		},
+		{
+			name: "object with module key and no return type",
+			sourceCode: `
+export class CacheModule {
+  static register(options) {
+    return { module: CacheModule, exports: [CacheService] };
+  }
+}
+`,
+			expectedExports: map[string][]string{"CacheModule": {"CacheService"}},
+		},
+		{
+			name: "class extending an aliased ConfigurableModuleClass",
+			sourceCode: `
+export const { ConfigurableModuleClass: BaseModule, MODULE_OPTIONS_TOKEN } =
+  new ConfigurableModuleBuilder<Options>().build();
+
+@Module({})
+export class HttpClientModule extends BaseModule {
+  static register(options: typeof OPTIONS_TYPE) {
+    return {
+      ...super.register(options),
+      providers: [HttpClient],
+      exports: [HttpClient],
+    };
+  }
+}
+`,
+			expectedExports:   map[string][]string{"HttpClientModule": {"HttpClient"}},
+			expectedProviders: map[string][]string{"HttpClientModule": {"HttpClient"}},
+		},
+		{
+			name: "instance methods and static helpers are ignored",
+			sourceCode: `
+export class Helpers {
+  static defaults() {
+    return { exports: [NotAnExport] };
+  }
+  build(): DynamicModule {
+    return { module: Helpers, exports: [AlsoNotAnExport] };
+  }
+}
+`,
+			expectedExports: map[string][]string{},
+		},
+	}
+
+	lang := typescript.GetLanguage()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := sitter.ParseCtx(context.Background(), []byte(tt.sourceCode), lang)
+			if err != nil {
+				t.Fatalf("Failed to parse source code: %v", err)
+			}
+
+			dynamicModules, err := parser.ParseDynamicModules(node, []byte(tt.sourceCode))
+			if err != nil {
+				t.Fatalf("Failed to get dynamic modules: %v", err)
+			}
+
+			exports := parser.MergeDynamicModuleExports(map[string][]string{}, dynamicModules)
+			for moduleName, expected := range tt.expectedExports {
+				if !reflect.DeepEqual(exports[moduleName], expected) {
+					t.Errorf("Expected exports %v for module %v, got %v", expected, moduleName, exports[moduleName])
+				}
+			}
+			if len(tt.expectedExports) == 0 && len(dynamicModules) != 0 {
+				t.Errorf("Expected no dynamic modules, got %v", dynamicModules)
+			}
+
+			providers := parser.MergeDynamicModuleProviders(map[string][]string{}, dynamicModules)
+			for moduleName, expected := range tt.expectedProviders {
+				if !reflect.DeepEqual(providers[moduleName], expected) {
+					t.Errorf("Expected providers %v for module %v, got %v", expected, moduleName, providers[moduleName])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package parser
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// These are defined by the order of the captures in the query, if the query is
+// changed this will need to be updated.
+const (
+	decoratorNameIndex     = uint32(0)
+	decoratorMetadataIndex = uint32(1)
+	decoratorModuleIndex   = uint32(2)
+)
+
+const (
+	nestCommonPackage   = "@nestjs/common"
+	moduleDecoratorName = "Module"
+	globalDecoratorName = "Global"
+	forwardRefName      = "forwardRef"
+
+	// maxConstResolutionDepth bounds how many const references are followed
+	// when resolving metadata, e.g. @Module(metadata) or imports: [...shared]
+	maxConstResolutionDepth = 8
+)
+
+// ModuleDeclaration is an exported class decorated with @Module(), an alias of
+// it, or a custom decorator that wraps Module metadata
+type ModuleDeclaration struct {
+	Name      string
+	Decorator string
+	// Metadata is the object literal holding the module metadata, either
+	// inline in the decorator call or resolved from a const declaration
+	Metadata *sitter.Node
+
+	root       *sitter.Node
+	arguments  *sitter.Node
+	statement  *sitter.Node
+	name       *sitter.Node
+	sourceCode []byte
+}
+
+// IsGlobal reports whether the module class is also decorated with @Global()
+func (d *ModuleDeclaration) IsGlobal() bool {
+	if d.statement == nil {
+		return false
+	}
+	for i := 0; i < int(d.statement.NamedChildCount()); i++ {
+		decorator := d.statement.NamedChild(i)
+		if decorator.Type() != "decorator" || decorator.NamedChildCount() == 0 {
+			continue
+		}
+		call := decorator.NamedChild(0)
+		if call.Type() == "call_expression" {
+			call = call.ChildByFieldName("function")
+		}
+		if call != nil && call.Content(d.sourceCode) == globalDecoratorName {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportedModuleNames returns the names of every module referenced in
+// imports: plain identifiers, the receivers of dynamic module calls such as
+// ConfigModule.forRoot() and the targets of forwardRef(() => UsersModule)
+func (d *ModuleDeclaration) ImportedModuleNames() []string {
+	var names []string
+	for _, entry := range d.arrayEntries("imports") {
+		if name := d.importedModuleName(entry); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// importedModuleName returns the module an imports entry refers to
+func (d *ModuleDeclaration) importedModuleName(entry *sitter.Node) string {
+	entry = unwrapExpression(entry)
+	if entry == nil {
+		return ""
+	}
+	switch entry.Type() {
+	case "identifier":
+		return entry.Content(d.sourceCode)
+	case "call_expression":
+		function := entry.ChildByFieldName("function")
+		arguments := entry.ChildByFieldName("arguments")
+		if function == nil {
+			return ""
+		}
+		if function.Type() == "member_expression" {
+			if object := function.ChildByFieldName("object"); object != nil && object.Type() == "identifier" {
+				return object.Content(d.sourceCode)
+			}
+			return ""
+		}
+		if function.Content(d.sourceCode) == forwardRefName && arguments != nil && arguments.NamedChildCount() > 0 {
+			if arrow := arguments.NamedChild(0); arrow.Type() == "arrow_function" {
+				return d.importedModuleName(arrow.ChildByFieldName("body"))
+			}
+		}
+	}
+	return ""
+}
+
+// ArrayNode returns the array stored under key when it is written inline in
+// the decorator call, so it can be edited
+func (d *ModuleDeclaration) ArrayNode(key string) *sitter.Node {
+	value := objectPair(d.Metadata, d.sourceCode, key)
+	if value == nil || value.Type() != "array" || !d.InDecorator(value) {
+		return nil
+	}
+	return value
+}
+
+// InDecorator reports whether n lies within the decorator call's arguments,
+// as opposed to const metadata declared elsewhere in the file
+func (d *ModuleDeclaration) InDecorator(n *sitter.Node) bool {
+	return d.arguments != nil && n != nil &&
+		n.StartByte() >= d.arguments.StartByte() && n.EndByte() <= d.arguments.EndByte()
+}
+
+// ArrayElements returns the identifier nodes listed under key in the module
+// metadata, following const references and spreads of const arrays
+func (d *ModuleDeclaration) ArrayElements(key string) []*sitter.Node {
+	var identifiers []*sitter.Node
+	for _, entry := range d.arrayEntries(key) {
+		if entry.Type() == "identifier" {
+			identifiers = append(identifiers, entry)
+		}
+	}
+	return identifiers
+}
+
+// ArrayElementNames returns the names of the identifiers listed under key
+func (d *ModuleDeclaration) ArrayElementNames(key string) []string {
+	var names []string
+	for _, element := range d.ArrayElements(key) {
+		names = append(names, element.Content(d.sourceCode))
+	}
+	return names
+}
+
+// ProviderClassNames returns the classes listed under key (providers or
+// controllers), including the useClass of provider definition objects
+func (d *ModuleDeclaration) ProviderClassNames(key string) []string {
+	var names []string
+	for _, entry := range d.arrayEntries(key) {
+		switch entry.Type() {
+		case "identifier":
+			names = append(names, entry.Content(d.sourceCode))
+		case "object":
+			if useClass := objectPair(entry, d.sourceCode, "useClass"); useClass != nil && useClass.Type() == "identifier" {
+				names = append(names, useClass.Content(d.sourceCode))
+			}
+		}
+	}
+	return names
+}
+
+// ProviderInjections returns the tokens that provider definition objects
+// inject directly, through useFactory inject arrays and useExisting aliases
+func (d *ModuleDeclaration) ProviderInjections() []string {
+	var tokens []string
+	for _, entry := range d.arrayEntries("providers") {
+		if entry.Type() != "object" {
+			continue
+		}
+		if existing := objectPair(entry, d.sourceCode, "useExisting"); existing != nil {
+			if token := tokenKey(existing, d.sourceCode); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+		inject := objectPair(entry, d.sourceCode, "inject")
+		if inject == nil || inject.Type() != "array" {
+			continue
+		}
+		for i := 0; i < int(inject.NamedChildCount()); i++ {
+			dependency := inject.NamedChild(i)
+			// Optional dependencies are written as { token: X, optional: true }
+			if dependency.Type() == "object" {
+				dependency = objectPair(dependency, d.sourceCode, "token")
+			}
+			if token := tokenKey(dependency, d.sourceCode); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// ExportTokens returns the identifiers and string token keys listed in exports
+func (d *ModuleDeclaration) ExportTokens() []string {
+	var tokens []string
+	for _, entry := range d.arrayEntries("exports") {
+		if token := tokenKey(entry, d.sourceCode); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// arrayEntries returns the element nodes of the array stored under key
+func (d *ModuleDeclaration) arrayEntries(key string) []*sitter.Node {
+	value := d.metadataValue(d.Metadata, key, 0)
+	return d.arrayValues(value, 0)
+}
+
+// metadataValue finds the value stored under key, looking through spreads of
+// other const metadata objects
+func (d *ModuleDeclaration) metadataValue(object *sitter.Node, key string, depth int) *sitter.Node {
+	if object == nil || depth > maxConstResolutionDepth {
+		return nil
+	}
+	if value := objectPair(object, d.sourceCode, key); value != nil {
+		if value.Type() == "shorthand_property_identifier" {
+			return resolveConst(d.root, d.sourceCode, value.Content(d.sourceCode), depth+1)
+		}
+		return unwrapExpression(value)
+	}
+	for i := 0; i < int(object.NamedChildCount()); i++ {
+		child := object.NamedChild(i)
+		if child.Type() != "spread_element" || child.NamedChildCount() == 0 {
+			continue
+		}
+		spread := unwrapExpression(child.NamedChild(0))
+		if spread.Type() != "identifier" {
+			continue
+		}
+		resolved := resolveConst(d.root, d.sourceCode, spread.Content(d.sourceCode), depth+1)
+		if resolved != nil && resolved.Type() == "object" {
+			if value := d.metadataValue(resolved, key, depth+1); value != nil {
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+// arrayValues returns the elements of an array value, following const
+// references and spreads of const arrays
+func (d *ModuleDeclaration) arrayValues(value *sitter.Node, depth int) []*sitter.Node {
+	if value == nil || depth > maxConstResolutionDepth {
+		return nil
+	}
+	if value.Type() == "identifier" {
+		value = resolveConst(d.root, d.sourceCode, value.Content(d.sourceCode), depth+1)
+		if value == nil {
+			return nil
+		}
+	}
+	if value.Type() != "array" {
+		return nil
+	}
+	var elements []*sitter.Node
+	for i := 0; i < int(value.NamedChildCount()); i++ {
+		element := value.NamedChild(i)
+		if element.Type() == "spread_element" {
+			if element.NamedChildCount() > 0 {
+				elements = append(elements, d.arrayValues(unwrapExpression(element.NamedChild(0)), depth+1)...)
+			}
+			continue
+		}
+		if element.Type() != "comment" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// ParseModuleDeclarations finds the module classes in a file. Besides
+// @Module(), decorators that alias Module from @nestjs/common and any of the
+// given custom decorator names are accepted. Metadata passed as a const
+// reference is resolved to its object literal.
+func ParseModuleDeclarations(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) ([]*ModuleDeclaration, error) {
+	moduleDecoratorQuery, err := LoadModuleDecoratorQuery()
+	if err != nil {
+		return nil, err
+	}
+	decoratorNames, namespaces := moduleDecoratorNames(node, sourceCode, customDecorators)
+
+	qc := sitter.NewQueryCursor()
+	qc.Exec(moduleDecoratorQuery, node)
+	var declarations []*ModuleDeclaration
+	for {
+		m, ok := qc.NextMatch()
+		if !ok {
+			break
+		}
+		var decoratorNode, metadataNode, moduleNameNode *sitter.Node
+		for _, c := range m.Captures {
+			switch c.Index {
+			case decoratorNameIndex:
+				decoratorNode = c.Node
+			case decoratorMetadataIndex:
+				metadataNode = c.Node
+			case decoratorModuleIndex:
+				moduleNameNode = c.Node
+			}
+		}
+		if decoratorNode == nil || metadataNode == nil || moduleNameNode == nil {
+			continue
+		}
+		if !isModuleDecorator(decoratorNode, sourceCode, decoratorNames, namespaces) {
+			continue
+		}
+		metadata := unwrapExpression(metadataNode)
+		if metadata.Type() == "identifier" {
+			metadata = resolveConst(node, sourceCode, metadata.Content(sourceCode), 0)
+		}
+		if metadata == nil || metadata.Type() != "object" {
+			continue
+		}
+		declarations = append(declarations, &ModuleDeclaration{
+			Name:       moduleNameNode.Content(sourceCode),
+			Decorator:  decoratorNode.Content(sourceCode),
+			Metadata:   metadata,
+			root:       node,
+			arguments:  metadataNode.Parent(),
+			statement:  moduleNameNode.Parent().Parent(),
+			name:       moduleNameNode,
+			sourceCode: sourceCode,
+		})
+	}
+	return declarations, nil
+}
+
+// parseModuleLists collects the names listed under the given metadata keys,
+// grouped by module name
+func parseModuleLists(
+	node *sitter.Node,
+	sourceCode []byte,
+	keys []string,
+	customDecorators []string,
+	namesForKey func(declaration *ModuleDeclaration, key string) []string,
+) (map[string][]string, error) {
+	declarations, err := ParseModuleDeclarations(node, sourceCode, customDecorators...)
+	if err != nil {
+		return nil, err
+	}
+	listsByModule := make(map[string][]string)
+	for _, declaration := range declarations {
+		for _, key := range keys {
+			names := namesForKey(declaration, key)
+			if len(names) == 0 {
+				continue
+			}
+			listsByModule[declaration.Name] = append(listsByModule[declaration.Name], names...)
+		}
+	}
+	return listsByModule, nil
+}
+
+// moduleDecoratorNames returns the local names that refer to Module from
+// @nestjs/common, plus the custom decorators, and the names of namespace
+// imports of @nestjs/common (import * as common from '@nestjs/common')
+func moduleDecoratorNames(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators []string,
+) (map[string]bool, map[string]bool) {
+	names := map[string]bool{moduleDecoratorName: true}
+	for _, decorator := range customDecorators {
+		names[decorator] = true
+	}
+	namespaces := make(map[string]bool)
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		statement := node.NamedChild(i)
+		if statement.Type() != "import_statement" {
+			continue
+		}
+		source := statement.ChildByFieldName("source")
+		if source == nil || stringLiteralValue(source, sourceCode) != nestCommonPackage {
+			continue
+		}
+		walkNodes(statement, func(n *sitter.Node) bool {
+			switch n.Type() {
+			case "import_specifier":
+				name := n.ChildByFieldName("name")
+				alias := n.ChildByFieldName("alias")
+				if name != nil && alias != nil && name.Content(sourceCode) == moduleDecoratorName {
+					names[alias.Content(sourceCode)] = true
+				}
+			case "namespace_import":
+				if n.NamedChildCount() > 0 {
+					namespaces[n.NamedChild(0).Content(sourceCode)] = true
+				}
+			}
+			return true
+		})
+	}
+	return names, namespaces
+}
+
+// isModuleDecorator checks a decorator's function against the accepted names
+func isModuleDecorator(
+	function *sitter.Node,
+	sourceCode []byte,
+	names map[string]bool,
+	namespaces map[string]bool,
+) bool {
+	switch function.Type() {
+	case "identifier":
+		return names[function.Content(sourceCode)]
+	case "member_expression":
+		object := function.ChildByFieldName("object")
+		property := function.ChildByFieldName("property")
+		return object != nil && property != nil &&
+			namespaces[object.Content(sourceCode)] &&
+			property.Content(sourceCode) == moduleDecoratorName
+	}
+	return false
+}
+
+// resolveConst finds the initializer of a top-level const declaration,
+// including exported ones
+func resolveConst(root *sitter.Node, sourceCode []byte, name string, depth int) *sitter.Node {
+	if depth > maxConstResolutionDepth {
+		return nil
+	}
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		statement := root.NamedChild(i)
+		if statement.Type() == "export_statement" {
+			if declaration := statement.ChildByFieldName("declaration"); declaration != nil {
+				statement = declaration
+			}
+		}
+		if statement.Type() != "lexical_declaration" {
+			continue
+		}
+		for j := 0; j < int(statement.NamedChildCount()); j++ {
+			declarator := statement.NamedChild(j)
+			if declarator.Type() != "variable_declarator" {
+				continue
+			}
+			nameNode := declarator.ChildByFieldName("name")
+			value := declarator.ChildByFieldName("value")
+			if nameNode == nil || value == nil || nameNode.Content(sourceCode) != name {
+				continue
+			}
+			value = unwrapExpression(value)
+			if value.Type() == "identifier" {
+				return resolveConst(root, sourceCode, value.Content(sourceCode), depth+1)
+			}
+			return value
+		}
+	}
+	return nil
+}
+
+// stringLiteralValue returns the contents of a string literal node
+func stringLiteralValue(n *sitter.Node, sourceCode []byte) string {
+	if n.Type() != "string" {
+		return ""
+	}
+	for i := 0; i < int(n.NamedChildCount()); i++ {
+		if fragment := n.NamedChild(i); fragment.Type() == "string_fragment" {
+			return fragment.Content(sourceCode)
+		}
+	}
+	return ""
+}
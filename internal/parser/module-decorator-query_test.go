@@ -0,0 +1,108 @@
+package parser_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseModuleImports_DecoratorVariants(t *testing.T) {
+	tests := []struct {
+		name             string
+		sourceCode       string
+		customDecorators []string
+		expected         map[string][]string
+	}{
+		{
+			name: "aliased Module import",
+			sourceCode: `
+import { Module as NestModule } from "@nestjs/common";
+@NestModule({
+  imports: [UsersModule],
+})
+export class AppModule {}
+`,
+			expected: map[string][]string{"AppModule": {"UsersModule"}},
+		},
+		{
+			name: "namespace import of @nestjs/common",
+			sourceCode: `
+import * as common from "@nestjs/common";
+@common.Module({
+  imports: [UsersModule],
+})
+export class AppModule {}
+`,
+			expected: map[string][]string{"AppModule": {"UsersModule"}},
+		},
+		{
+			name: "metadata from a const object",
+			sourceCode: `
+import { Module, ModuleMetadata } from "@nestjs/common";
+const sharedImports = [ConfigModule];
+const metadata: ModuleMetadata = {
+  imports: [...sharedImports, UsersModule],
+};
+@Module(metadata)
+export class AppModule {}
+`,
+			expected: map[string][]string{"AppModule": {"ConfigModule", "UsersModule"}},
+		},
+		{
+			name: "metadata spread from another const",
+			sourceCode: `
+import { Module } from "@nestjs/common";
+export const baseMetadata = { imports: [ConfigModule] };
+@Module({ ...baseMetadata, providers: [AppService] })
+export class AppModule {}
+`,
+			expected: map[string][]string{"AppModule": {"ConfigModule"}},
+		},
+		{
+			name: "custom wrapper decorator",
+			sourceCode: `
+import { FeatureModule } from "./feature-module.decorator";
+@FeatureModule({
+  imports: [UsersModule],
+})
+export class BillingModule {}
+`,
+			customDecorators: []string{"FeatureModule"},
+			expected:         map[string][]string{"BillingModule": {"UsersModule"}},
+		},
+		{
+			name: "unknown decorators are ignored",
+			sourceCode: `
+import { FeatureModule } from "./feature-module.decorator";
+@FeatureModule({
+  imports: [UsersModule],
+})
+export class BillingModule {}
+`,
+			expected: map[string][]string{},
+		},
+	}
+
+	lang := typescript.GetLanguage()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := sitter.ParseCtx(context.Background(), []byte(tt.sourceCode), lang)
+			if err != nil {
+				t.Fatalf("Failed to parse source code: %v", err)
+			}
+
+			importsByModule, err := parser.ParseModuleImports(node, []byte(tt.sourceCode), tt.customDecorators...)
+			if err != nil {
+				t.Fatalf("Failed to get imports by module: %v", err)
+			}
+
+			if !reflect.DeepEqual(importsByModule, tt.expected) {
+				t.Errorf("Expected imports %v, got %v", tt.expected, importsByModule)
+			}
+		})
+	}
+}
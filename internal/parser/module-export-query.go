@@ -2,53 +2,15 @@ package parser
 
 import sitter "github.com/smacker/go-tree-sitter"
 
-// These are defined by the order of the captures in the query, if the query is
-// changed this will need to be updated.
-const (
-	exportListIndex       = uint32(2)
-	exportModuleNameIndex = uint32(3)
-)
-
+// ParseModuleExports returns the identifiers in the exports array of each
+// module's metadata, grouped by module name
 func ParseModuleExports(
 	node *sitter.Node,
 	sourceCode []byte,
+	customDecorators ...string,
 ) (map[string][]string, error) {
-	exportsQuery, err := LoadModuleExportQuery()
-	if err != nil {
-		return nil, err
-	}
-	// Parse source code
-	qc := sitter.NewQueryCursor()
-	qc.Exec(exportsQuery, node)
-	exportsByModule := make(map[string][]string)
-	for {
-		m, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-		// Apply predicates filtering
-		m = qc.FilterPredicates(m, sourceCode)
-		currPair := struct {
-			moduleName string
-			exportName string
-		}{}
-		for _, c := range m.Captures {
-			if c.Index == exportModuleNameIndex {
-				currPair.moduleName = c.Node.Content(sourceCode)
-			} else if c.Index == exportListIndex {
-				currPair.exportName = c.Node.Content(sourceCode)
-			}
-		}
-		if currPair.exportName == "" || currPair.moduleName == "" {
-			continue
-		}
-		if _, ok = exportsByModule[currPair.moduleName]; !ok {
-			exportsByModule[currPair.moduleName] = []string{}
-		}
-		exportsByModule[currPair.moduleName] = append(
-			exportsByModule[currPair.moduleName],
-			currPair.exportName,
-		)
-	}
-	return exportsByModule, nil
+	return parseModuleLists(node, sourceCode, []string{"exports"}, customDecorators,
+		func(declaration *ModuleDeclaration, _ string) []string {
+			return declaration.ExportTokens()
+		})
 }
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Locations returns where the module class name and the elements of its
+// imports, providers and exports, ORM feature entities and Bull queues are
+// declared. Elements are named like the analysis names them: imports by the
+// module they refer to, providers by class and provide token, exports by
+// token and queues by display name.
+func (d *ModuleDeclaration) Locations() analysis.ModuleLocations {
+	locations := analysis.ModuleLocations{
+		Declaration: NodeLocation(d.name),
+		Elements:    make(map[string]map[string]analysis.Location),
+	}
+	add := func(key, name string, n *sitter.Node) {
+		if name == "" {
+			return
+		}
+		if locations.Elements[key] == nil {
+			locations.Elements[key] = make(map[string]analysis.Location)
+		}
+		if _, exists := locations.Elements[key][name]; !exists {
+			locations.Elements[key][name] = NodeLocation(n)
+		}
+	}
+
+	for _, entry := range d.arrayEntries("imports") {
+		add("imports", d.importedModuleName(entry), entry)
+	}
+	for _, entry := range d.arrayEntries("providers") {
+		switch entry.Type() {
+		case "identifier":
+			add("providers", entry.Content(d.sourceCode), entry)
+		case "object":
+			add("providers", tokenKey(objectPair(entry, d.sourceCode, "provide"), d.sourceCode), entry)
+			if useClass := objectPair(entry, d.sourceCode, "useClass"); useClass != nil && useClass.Type() == "identifier" {
+				add("providers", useClass.Content(d.sourceCode), entry)
+			}
+		}
+	}
+	for _, entry := range d.arrayEntries("exports") {
+		add("exports", tokenKey(entry, d.sourceCode), entry)
+	}
+	for _, registration := range d.FeatureRegistrations() {
+		add(analysis.ElementEntities, registration.Name, registration.Node)
+	}
+	for _, registration := range d.QueueRegistrations() {
+		add(analysis.ElementQueues, analysis.TokenDisplayName(registration.Name), registration.Node)
+	}
+	return locations
+}
+
+// ParseModuleLocations returns the locations of each module's declaration and
+// metadata elements, by module name
+func ParseModuleLocations(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) (map[string]analysis.ModuleLocations, error) {
+	declarations, err := ParseModuleDeclarations(node, sourceCode, customDecorators...)
+	if err != nil {
+		return nil, err
+	}
+	locationsByModule := make(map[string]analysis.ModuleLocations)
+	for _, declaration := range declarations {
+		if _, exists := locationsByModule[declaration.Name]; !exists {
+			locationsByModule[declaration.Name] = declaration.Locations()
+		}
+	}
+	return locationsByModule, nil
+}
+
+// NodeLocation converts the range of a syntax node to a location
+func NodeLocation(n *sitter.Node) analysis.Location {
+	return analysis.Location{
+		StartLine:   int(n.StartPoint().Row) + 1,
+		StartColumn: int(n.StartPoint().Column) + 1,
+		EndLine:     int(n.EndPoint().Row) + 1,
+		EndColumn:   int(n.EndPoint().Column) + 1,
+		StartByte:   int(n.StartByte()),
+		EndByte:     int(n.EndByte()),
+	}
+}
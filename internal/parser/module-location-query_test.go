@@ -0,0 +1,77 @@
+package parser_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseModuleLocations(t *testing.T) {
+	sourceCode := `import { Module } from "@nestjs/common";
+
+@Module({
+  imports: [
+    ConfigModule.forRoot({ isGlobal: true }),
+    TypeOrmModule.forFeature([User]),
+    BullModule.registerQueue({ name: 'emails' }),
+  ],
+  providers: [UsersService, { provide: 'CACHE', useClass: RedisCache }],
+  exports: [UsersService],
+})
+export class UsersModule {}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	locationsByModule, err := parser.ParseModuleLocations(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get module locations: %v", err)
+	}
+	locations, ok := locationsByModule["UsersModule"]
+	if !ok {
+		t.Fatalf("Expected locations for UsersModule, got %v", locationsByModule)
+	}
+
+	tests := []struct {
+		key      string
+		name     string
+		expected string
+		line     int
+		column   int
+	}{
+		{"imports", "ConfigModule", "ConfigModule.forRoot({ isGlobal: true })", 5, 5},
+		{"imports", "TypeOrmModule", "TypeOrmModule.forFeature([User])", 6, 5},
+		{"providers", "UsersService", "UsersService", 9, 15},
+		{"providers", "'CACHE'", "{ provide: 'CACHE', useClass: RedisCache }", 9, 29},
+		{"providers", "RedisCache", "{ provide: 'CACHE', useClass: RedisCache }", 9, 29},
+		{"exports", "UsersService", "UsersService", 10, 13},
+		{analysis.ElementEntities, "User", "User", 6, 31},
+		{analysis.ElementQueues, "emails", "{ name: 'emails' }", 7, 30},
+	}
+	for _, tt := range tests {
+		location, ok := locations.Element(tt.key, tt.name)
+		if !ok {
+			t.Errorf("Expected a location for %s %s", tt.key, tt.name)
+			continue
+		}
+		if content := sourceCode[location.StartByte:location.EndByte]; content != tt.expected {
+			t.Errorf("Expected %s %s to span %q, got %q", tt.key, tt.name, tt.expected, content)
+		}
+		if location.StartLine != tt.line || location.StartColumn != tt.column {
+			t.Errorf("Expected %s %s at %d:%d, got %d:%d", tt.key, tt.name, tt.line, tt.column, location.StartLine, location.StartColumn)
+		}
+	}
+
+	start := len(sourceCode) - len("UsersModule {}\n")
+	expected := analysis.Location{StartLine: 12, StartColumn: 14, EndLine: 12, EndColumn: 25, StartByte: start, EndByte: start + len("UsersModule")}
+	if locations.Declaration != expected {
+		t.Errorf("Expected the declaration at %v, got %v", expected, locations.Declaration)
+	}
+}
@@ -11,12 +11,15 @@ import (
 // ModuleParser implements the analysis.ModuleParser interface
 type ModuleParser struct {
 	lang *sitter.Language
+	// moduleDecorators lists custom decorators that wrap @Module() metadata
+	moduleDecorators []string
 }
 
 // NewModuleParser creates a new module parser
-func NewModuleParser(lang *sitter.Language) *ModuleParser {
+func NewModuleParser(lang *sitter.Language, moduleDecorators ...string) *ModuleParser {
 	return &ModuleParser{
-		lang: lang,
+		lang:             lang,
+		moduleDecorators: moduleDecorators,
 	}
 }
 
@@ -33,23 +36,31 @@ func (p *ModuleParser) ParseModuleInfo(filePath string) (*analysis.ModuleInfo, e
 	}
 
 	// Get imports by module
-	importsByModule, err := ParseModuleImports(n, sourceCode)
+	importsByModule, err := ParseModuleImports(n, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get exports by module
-	exportsByModule, err := ParseModuleExports(n, sourceCode)
+	exportsByModule, err := ParseModuleExports(n, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get providers by module
-	providersByModule, err := ParseModuleProviders(n, sourceCode)
+	providersByModule, err := ParseModuleProviders(n, sourceCode, p.moduleDecorators...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Merge in metadata returned from static DynamicModule factories
+	dynamicModules, err := ParseDynamicModules(n, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	exportsByModule = MergeDynamicModuleExports(exportsByModule, dynamicModules)
+	providersByModule = MergeDynamicModuleProviders(providersByModule, dynamicModules)
+
 	// For simplicity, take the first module found
 	// In practice, most files have one module
 	for moduleName := range importsByModule {
@@ -84,7 +95,7 @@ func (p *ModuleParser) GetImportsByModule(filePath string) (map[string][]string,
 		return nil, err
 	}
 
-	return ParseModuleImports(n, sourceCode)
+	return ParseModuleImports(n, sourceCode, p.moduleDecorators...)
 }
 
 // GetExportsByModule returns exports grouped by module name
@@ -99,7 +110,16 @@ func (p *ModuleParser) GetExportsByModule(filePath string) (map[string][]string,
 		return nil, err
 	}
 
-	return ParseModuleExports(n, sourceCode)
+	exportsByModule, err := ParseModuleExports(n, sourceCode, p.moduleDecorators...)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicModules, err := ParseDynamicModules(n, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	return MergeDynamicModuleExports(exportsByModule, dynamicModules), nil
 }
 
 // GetProvidersByModule returns providers grouped by module name
@@ -114,5 +134,14 @@ func (p *ModuleParser) GetProvidersByModule(filePath string) (map[string][]strin
 		return nil, err
 	}
 
-	return ParseModuleProviders(n, sourceCode)
+	providersByModule, err := ParseModuleProviders(n, sourceCode, p.moduleDecorators...)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicModules, err := ParseDynamicModules(n, sourceCode)
+	if err != nil {
+		return nil, err
+	}
+	return MergeDynamicModuleProviders(providersByModule, dynamicModules), nil
 }
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+const forFeatureMethod = "forFeature"
+
+// ormFeatureModules are the ORM modules whose forFeature() registers entities
+var ormFeatureModules = map[string]bool{
+	"TypeOrmModule":   true,
+	"MongooseModule":  true,
+	"SequelizeModule": true,
+}
+
+// modelInjectors are the decorators and token helpers that inject a
+// repository or model for an entity
+var modelInjectors = map[string]bool{
+	"InjectRepository":   true,
+	"InjectModel":        true,
+	"getRepositoryToken": true,
+	"getModelToken":      true,
+}
+
+// FeatureRegistration is an entity registered in a module's imports through
+// an ORM module's forFeature()
+type FeatureRegistration struct {
+	Module string
+	Name   string
+	// Node is the entity's element in the forFeature() array
+	Node *sitter.Node
+}
+
+// FeatureRegistrations returns the entities registered with forFeature() calls
+// in the module's imports, e.g. TypeOrmModule.forFeature([User, Order]) or
+// MongooseModule.forFeature([{ name: Cat.name, schema: CatSchema }])
+func (d *ModuleDeclaration) FeatureRegistrations() []FeatureRegistration {
+	var registrations []FeatureRegistration
+	for _, entry := range d.arrayEntries("imports") {
+		ormModule, arguments := staticCall(entry, d.sourceCode, forFeatureMethod)
+		if !ormFeatureModules[ormModule] || arguments == nil || arguments.NamedChildCount() == 0 {
+			continue
+		}
+		for _, element := range d.arrayValues(unwrapExpression(arguments.NamedChild(0)), 0) {
+			entity := element
+			if element.Type() == "object" {
+				entity = objectPair(element, d.sourceCode, "name")
+			}
+			if name := entityName(entity, d.sourceCode); name != "" {
+				registrations = append(registrations, FeatureRegistration{
+					Module: ormModule,
+					Name:   name,
+					Node:   element,
+				})
+			}
+		}
+	}
+	return registrations
+}
+
+// ParseFeatureEntities returns the entities registered with ORM forFeature()
+// calls in each module's imports, grouped by module name
+func ParseFeatureEntities(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) (map[string][]analysis.FeatureEntity, error) {
+	declarations, err := ParseModuleDeclarations(node, sourceCode, customDecorators...)
+	if err != nil {
+		return nil, err
+	}
+	entitiesByModule := make(map[string][]analysis.FeatureEntity)
+	for _, declaration := range declarations {
+		for _, registration := range declaration.FeatureRegistrations() {
+			entitiesByModule[declaration.Name] = append(entitiesByModule[declaration.Name], analysis.FeatureEntity{
+				Module: registration.Module,
+				Name:   registration.Name,
+			})
+		}
+	}
+	return entitiesByModule, nil
+}
+
+// ParseInjectedModels returns the entity names whose repository or model is
+// injected, e.g. @InjectRepository(User) or @InjectModel(Cat.name)
+func ParseInjectedModels(
+	node *sitter.Node,
+	sourceCode []byte,
+) ([]string, error) {
+	var models []string
+	walkNodes(node, func(n *sitter.Node) bool {
+		if n.Type() != "call_expression" {
+			return true
+		}
+		function := n.ChildByFieldName("function")
+		arguments := n.ChildByFieldName("arguments")
+		if function == nil || arguments == nil || !modelInjectors[function.Content(sourceCode)] {
+			return true
+		}
+		if arguments.NamedChildCount() > 0 {
+			if name := entityName(arguments.NamedChild(0), sourceCode); name != "" {
+				models = append(models, name)
+			}
+		}
+		return false
+	})
+	return models, nil
+}
+
+// staticCall matches calls like TypeOrmModule.forFeature(...) and returns the
+// receiver name and the arguments node when the method name matches
+func staticCall(n *sitter.Node, sourceCode []byte, method string) (string, *sitter.Node) {
+	n = unwrapExpression(n)
+	if n == nil || n.Type() != "call_expression" {
+		return "", nil
+	}
+	function := n.ChildByFieldName("function")
+	if function == nil || function.Type() != "member_expression" {
+		return "", nil
+	}
+	object := function.ChildByFieldName("object")
+	property := function.ChildByFieldName("property")
+	if object == nil || property == nil || property.Content(sourceCode) != method {
+		return "", nil
+	}
+	return object.Content(sourceCode), n.ChildByFieldName("arguments")
+}
+
+// entityName derives an entity name from a class identifier, a Class.name
+// member expression or a string literal
+func entityName(n *sitter.Node, sourceCode []byte) string {
+	n = unwrapExpression(n)
+	if n == nil {
+		return ""
+	}
+	switch n.Type() {
+	case "identifier":
+		return n.Content(sourceCode)
+	case "member_expression":
+		object := n.ChildByFieldName("object")
+		property := n.ChildByFieldName("property")
+		if object != nil && property != nil && object.Type() == "identifier" && property.Content(sourceCode) == "name" {
+			return object.Content(sourceCode)
+		}
+	default:
+		if value, ok := stringValue(n, sourceCode); ok {
+			return value
+		}
+	}
+	return ""
+}
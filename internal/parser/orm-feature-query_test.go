@@ -0,0 +1,91 @@
+package parser_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseFeatureEntities(t *testing.T) {
+	sourceCode := `
+import { Module } from "@nestjs/common";
+import { TypeOrmModule } from "@nestjs/typeorm";
+import { MongooseModule } from "@nestjs/mongoose";
+import { SequelizeModule } from "@nestjs/sequelize";
+
+@Module({
+  imports: [
+    TypeOrmModule.forFeature([User, Order]),
+    MongooseModule.forFeature([{ name: Cat.name, schema: CatSchema }, { name: 'Dog', schema: DogSchema }]),
+    SequelizeModule.forFeature([Invoice]),
+    ConfigModule.forFeature(databaseConfig),
+    SharedModule,
+  ],
+  providers: [UsersService],
+})
+export class UsersModule {}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	entities, err := parser.ParseFeatureEntities(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get feature entities: %v", err)
+	}
+
+	expected := map[string][]analysis.FeatureEntity{
+		"UsersModule": {
+			{Module: "TypeOrmModule", Name: "User"},
+			{Module: "TypeOrmModule", Name: "Order"},
+			{Module: "MongooseModule", Name: "Cat"},
+			{Module: "MongooseModule", Name: "Dog"},
+			{Module: "SequelizeModule", Name: "Invoice"},
+		},
+	}
+	if !reflect.DeepEqual(entities, expected) {
+		t.Errorf("Expected entities %v, got %v", expected, entities)
+	}
+}
+
+func TestParseInjectedModels(t *testing.T) {
+	sourceCode := `
+import { Injectable } from "@nestjs/common";
+@Injectable()
+export class UsersService {
+  constructor(
+    @InjectRepository(User) private readonly users: Repository<User>,
+    @InjectModel(Cat.name) private readonly cats: Model<Cat>,
+    @InjectModel('Dog') private readonly dogs: Model<Dog>,
+  ) {}
+}
+
+export const orderProvider = {
+  provide: 'ORDERS',
+  useFactory: (orders) => orders,
+  inject: [getRepositoryToken(Order)],
+};
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	models, err := parser.ParseInjectedModels(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get injected models: %v", err)
+	}
+
+	expected := []string{"User", "Cat", "Dog", "Order"}
+	if !reflect.DeepEqual(models, expected) {
+		t.Errorf("Expected models %v, got %v", expected, models)
+	}
+}
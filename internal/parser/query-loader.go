@@ -12,45 +12,40 @@ var (
 	typescriptLang = typescript.GetLanguage()
 
 	// Cached compiled queries
-	moduleImportQueryCache     *sitter.Query
+	moduleDecoratorQueryCache  *sitter.Query
 	importPathQueryCache       *sitter.Query
-	moduleExportQueryCache     *sitter.Query
-	moduleProviderQueryCache   *sitter.Query
 	classInheritanceQueryCache *sitter.Query
+	dynamicModuleQueryCache    *sitter.Query
 
 	// Sync guards for one-time initialization
-	moduleImportQueryOnce     sync.Once
+	moduleDecoratorQueryOnce  sync.Once
 	importPathQueryOnce       sync.Once
-	moduleExportQueryOnce     sync.Once
-	moduleProviderQueryOnce   sync.Once
 	classInheritanceQueryOnce sync.Once
+	dynamicModuleQueryOnce    sync.Once
 )
 
-//go:embed module-imports.query
-var moduleImportsQuery string
+//go:embed module-decorators.query
+var moduleDecoratorsQuery string
 
 //go:embed import-paths.query
 var importPathsQuery string
 
-//go:embed module-exports.query
-var moduleExportsQuery string
-
-//go:embed module-provider-controller.query
-var moduleProviderControllerQuery string
-
 //go:embed class-inheritance.query
 var classInheritanceQuery string
 
+//go:embed dynamic-modules.query
+var dynamicModulesQuery string
+
 func queryFromString(queryContent string) (*sitter.Query, error) {
 	return sitter.NewQuery([]byte(queryContent), typescriptLang)
 }
 
-func LoadModuleImportQuery() (*sitter.Query, error) {
+func LoadModuleDecoratorQuery() (*sitter.Query, error) {
 	var err error
-	moduleImportQueryOnce.Do(func() {
-		moduleImportQueryCache, err = queryFromString(moduleImportsQuery)
+	moduleDecoratorQueryOnce.Do(func() {
+		moduleDecoratorQueryCache, err = queryFromString(moduleDecoratorsQuery)
 	})
-	return moduleImportQueryCache, err
+	return moduleDecoratorQueryCache, err
 }
 
 func LoadImportPathQuery() (*sitter.Query, error) {
@@ -61,22 +56,6 @@ func LoadImportPathQuery() (*sitter.Query, error) {
 	return importPathQueryCache, err
 }
 
-func LoadModuleExportQuery() (*sitter.Query, error) {
-	var err error
-	moduleExportQueryOnce.Do(func() {
-		moduleExportQueryCache, err = queryFromString(moduleExportsQuery)
-	})
-	return moduleExportQueryCache, err
-}
-
-func LoadModuleProviderControllerQuery() (*sitter.Query, error) {
-	var err error
-	moduleProviderQueryOnce.Do(func() {
-		moduleProviderQueryCache, err = queryFromString(moduleProviderControllerQuery)
-	})
-	return moduleProviderQueryCache, err
-}
-
 func LoadClassInheritanceQuery() (*sitter.Query, error) {
 	var err error
 	classInheritanceQueryOnce.Do(func() {
@@ -84,3 +63,11 @@ func LoadClassInheritanceQuery() (*sitter.Query, error) {
 	})
 	return classInheritanceQueryCache, err
 }
+
+func LoadDynamicModuleQuery() (*sitter.Query, error) {
+	var err error
+	dynamicModuleQueryOnce.Do(func() {
+		dynamicModuleQueryCache, err = queryFromString(dynamicModulesQuery)
+	})
+	return dynamicModuleQueryCache, err
+}
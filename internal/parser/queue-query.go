@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+const bullModuleName = analysis.BullModuleName
+
+// registerQueueMethods are the BullModule methods that register queues
+var registerQueueMethods = []string{"registerQueue", "registerQueueAsync"}
+
+// queueDecorators are the decorators that consume or produce a queue
+var queueDecorators = map[string]bool{
+	analysis.QueueInjectDecorator:    true,
+	analysis.QueueProcessorDecorator: true,
+}
+
+// QueueRegistration is a queue registered in a module's imports through
+// BullModule.registerQueue() or registerQueueAsync()
+type QueueRegistration struct {
+	// Name is the queue name as a token key: an identifier name or a string
+	// token key
+	Name string
+	// Node is the options object that registers the queue
+	Node *sitter.Node
+}
+
+// QueueRegistrations returns the queues registered in the module's imports,
+// e.g. BullModule.registerQueue({ name: 'emails' }, { name: 'reports' })
+func (d *ModuleDeclaration) QueueRegistrations() []QueueRegistration {
+	var registrations []QueueRegistration
+	for _, entry := range d.arrayEntries("imports") {
+		for _, method := range registerQueueMethods {
+			receiver, arguments := staticCall(entry, d.sourceCode, method)
+			if receiver != bullModuleName || arguments == nil {
+				continue
+			}
+			for i := 0; i < int(arguments.NamedChildCount()); i++ {
+				options := unwrapExpression(arguments.NamedChild(i))
+				if options == nil || options.Type() != "object" {
+					continue
+				}
+				if name := tokenKey(objectPair(options, d.sourceCode, "name"), d.sourceCode); name != "" {
+					registrations = append(registrations, QueueRegistration{
+						Name: name,
+						Node: options,
+					})
+				}
+			}
+		}
+	}
+	return registrations
+}
+
+// ParseQueueRegistrations returns the queue names registered in each module's
+// imports, grouped by module name
+func ParseQueueRegistrations(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) (map[string][]string, error) {
+	declarations, err := ParseModuleDeclarations(node, sourceCode, customDecorators...)
+	if err != nil {
+		return nil, err
+	}
+	queuesByModule := make(map[string][]string)
+	for _, declaration := range declarations {
+		for _, registration := range declaration.QueueRegistrations() {
+			queuesByModule[declaration.Name] = append(queuesByModule[declaration.Name], registration.Name)
+		}
+	}
+	return queuesByModule, nil
+}
+
+// ParseQueueUsages returns the queues used through @InjectQueue() and
+// @Processor() decorators, e.g. @InjectQueue('emails') or
+// @Processor({ name: 'emails' })
+func ParseQueueUsages(
+	node *sitter.Node,
+	sourceCode []byte,
+) ([]analysis.QueueUsage, error) {
+	var usages []analysis.QueueUsage
+	walkNodes(node, func(n *sitter.Node) bool {
+		if n.Type() != "decorator" || n.NamedChildCount() == 0 {
+			return true
+		}
+		call := n.NamedChild(0)
+		if call.Type() != "call_expression" {
+			return false
+		}
+		function := call.ChildByFieldName("function")
+		arguments := call.ChildByFieldName("arguments")
+		if function == nil || arguments == nil || !queueDecorators[function.Content(sourceCode)] {
+			return false
+		}
+		if arguments.NamedChildCount() == 0 {
+			return false
+		}
+		queue := unwrapExpression(arguments.NamedChild(0))
+		if queue != nil && queue.Type() == "object" {
+			queue = objectPair(queue, sourceCode, "name")
+		}
+		if name := tokenKey(queue, sourceCode); name != "" {
+			usages = append(usages, analysis.QueueUsage{
+				Name:      name,
+				Decorator: function.Content(sourceCode),
+			})
+		}
+		return false
+	})
+	return usages, nil
+}
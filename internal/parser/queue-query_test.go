@@ -0,0 +1,84 @@
+package parser_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseQueueRegistrations(t *testing.T) {
+	sourceCode := `
+import { Module } from "@nestjs/common";
+import { BullModule } from "@nestjs/bullmq";
+import { REPORTS_QUEUE } from "./queues";
+
+@Module({
+  imports: [
+    BullModule.forRoot({ connection: { host: 'localhost' } }),
+    BullModule.registerQueue({ name: 'emails' }, { name: REPORTS_QUEUE }),
+    BullModule.registerQueueAsync({ name: 'audio', useFactory: () => ({}) }),
+  ],
+  providers: [EmailsService],
+})
+export class JobsModule {}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	queues, err := parser.ParseQueueRegistrations(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get queue registrations: %v", err)
+	}
+
+	expected := map[string][]string{
+		"JobsModule": {"'emails'", "REPORTS_QUEUE", "'audio'"},
+	}
+	if !reflect.DeepEqual(queues, expected) {
+		t.Errorf("Expected queues %v, got %v", expected, queues)
+	}
+}
+
+func TestParseQueueUsages(t *testing.T) {
+	sourceCode := `
+import { Injectable } from "@nestjs/common";
+import { InjectQueue, Processor } from "@nestjs/bullmq";
+
+@Injectable()
+export class EmailsService {
+  constructor(
+    @InjectQueue('emails') private readonly emails: Queue,
+    @InjectQueue(REPORTS_QUEUE) private readonly reports: Queue,
+  ) {}
+}
+
+@Processor({ name: 'audio' })
+export class AudioConsumer {}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	usages, err := parser.ParseQueueUsages(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get queue usages: %v", err)
+	}
+
+	expected := []analysis.QueueUsage{
+		{Name: "'emails'", Decorator: analysis.QueueInjectDecorator},
+		{Name: "REPORTS_QUEUE", Decorator: analysis.QueueInjectDecorator},
+		{Name: "'audio'", Decorator: analysis.QueueProcessorDecorator},
+	}
+	if !reflect.DeepEqual(usages, expected) {
+		t.Errorf("Expected usages %v, got %v", expected, usages)
+	}
+}
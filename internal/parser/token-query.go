@@ -0,0 +1,178 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+const injectDecoratorName = "Inject"
+
+// ParseTokenDeclarations finds top-level const declarations and returns the
+// string token key of each const initialised with a string literal, e.g.
+// export const MAILER_TOKEN = 'MAILER'. Consts with other values, e.g.
+// Symbol('MAILER'), have an empty key since they can only be compared by
+// their declaration.
+func ParseTokenDeclarations(
+	node *sitter.Node,
+	sourceCode []byte,
+) (map[string]string, error) {
+	declarations := make(map[string]string)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		statement := node.NamedChild(i)
+		if statement.Type() == "export_statement" {
+			if declaration := statement.ChildByFieldName("declaration"); declaration != nil {
+				statement = declaration
+			}
+		}
+		if statement.Type() != "lexical_declaration" {
+			continue
+		}
+		if kind := statement.ChildByFieldName("kind"); kind == nil || kind.Content(sourceCode) != "const" {
+			continue
+		}
+		for j := 0; j < int(statement.NamedChildCount()); j++ {
+			declarator := statement.NamedChild(j)
+			if declarator.Type() != "variable_declarator" {
+				continue
+			}
+			name := declarator.ChildByFieldName("name")
+			value := declarator.ChildByFieldName("value")
+			if name == nil || value == nil || name.Type() != "identifier" {
+				continue
+			}
+			key := ""
+			if value, ok := stringValue(unwrapExpression(value), sourceCode); ok {
+				key = analysis.StringTokenKey(value)
+			}
+			declarations[name.Content(sourceCode)] = key
+		}
+	}
+	return declarations, nil
+}
+
+// ParseReExports returns the import paths a file re-exports names from, e.g.
+// export { MAILER_TOKEN } from './mailer.tokens', by name. The paths of
+// export * from statements are listed under "*". Renamed re-exports are left
+// out, since their names differ from the declarations.
+func ParseReExports(
+	node *sitter.Node,
+	sourceCode []byte,
+) (map[string][]string, error) {
+	reExports := make(map[string][]string)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		statement := node.NamedChild(i)
+		if statement.Type() != "export_statement" {
+			continue
+		}
+		source := statement.ChildByFieldName("source")
+		if source == nil {
+			continue
+		}
+		path := stringLiteralValue(source, sourceCode)
+		for j := 0; j < int(statement.ChildCount()); j++ {
+			child := statement.Child(j)
+			switch child.Type() {
+			case "*":
+				reExports["*"] = append(reExports["*"], path)
+			case "export_clause":
+				for k := 0; k < int(child.NamedChildCount()); k++ {
+					specifier := child.NamedChild(k)
+					name := specifier.ChildByFieldName("name")
+					if name == nil || specifier.ChildByFieldName("alias") != nil {
+						continue
+					}
+					reExports[name.Content(sourceCode)] = append(reExports[name.Content(sourceCode)], path)
+				}
+			}
+		}
+	}
+	return reExports, nil
+}
+
+// ParseInjectedTokens returns the tokens passed to @Inject() decorators, as
+// identifier names or string token keys
+func ParseInjectedTokens(
+	node *sitter.Node,
+	sourceCode []byte,
+) ([]string, error) {
+	var tokens []string
+	walkNodes(node, func(n *sitter.Node) bool {
+		if n.Type() != "decorator" || n.NamedChildCount() == 0 {
+			return true
+		}
+		call := n.NamedChild(0)
+		if call.Type() != "call_expression" {
+			return false
+		}
+		function := call.ChildByFieldName("function")
+		arguments := call.ChildByFieldName("arguments")
+		if function == nil || arguments == nil || function.Content(sourceCode) != injectDecoratorName {
+			return false
+		}
+		if arguments.NamedChildCount() > 0 {
+			if token := tokenKey(arguments.NamedChild(0), sourceCode); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+		return false
+	})
+	return tokens, nil
+}
+
+// ParseModuleProviderInjections returns the tokens that provider definitions
+// in module metadata inject directly, through useFactory inject arrays and
+// useExisting aliases, grouped by module name
+func ParseModuleProviderInjections(
+	node *sitter.Node,
+	sourceCode []byte,
+	customDecorators ...string,
+) (map[string][]string, error) {
+	declarations, err := ParseModuleDeclarations(node, sourceCode, customDecorators...)
+	if err != nil {
+		return nil, err
+	}
+	injectionsByModule := make(map[string][]string)
+	for _, declaration := range declarations {
+		if injections := declaration.ProviderInjections(); len(injections) > 0 {
+			injectionsByModule[declaration.Name] = append(injectionsByModule[declaration.Name], injections...)
+		}
+	}
+	return injectionsByModule, nil
+}
+
+// tokenKey converts a token expression to an identifier name or string token key
+func tokenKey(n *sitter.Node, sourceCode []byte) string {
+	n = unwrapExpression(n)
+	if n == nil {
+		return ""
+	}
+	if n.Type() == "identifier" {
+		return n.Content(sourceCode)
+	}
+	if value, ok := stringValue(n, sourceCode); ok {
+		return analysis.StringTokenKey(value)
+	}
+	return ""
+}
+
+// stringValue returns the value of a string literal or a template string
+// without substitutions
+func stringValue(n *sitter.Node, sourceCode []byte) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	switch n.Type() {
+	case "string":
+		return stringLiteralValue(n, sourceCode), true
+	case "template_string":
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			if n.NamedChild(i).Type() == "template_substitution" {
+				return "", false
+			}
+		}
+		return strings.Trim(n.Content(sourceCode), "`"), true
+	}
+	return "", false
+}
@@ -0,0 +1,150 @@
+package parser_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/parser"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+func TestParseTokenDeclarations(t *testing.T) {
+	sourceCode := `
+export const MAILER_TOKEN = 'MAILER';
+const CACHE_TOKEN = ` + "`CACHE`" + `;
+export const EVENTS_TOKEN = Symbol('EVENTS');
+const prefix = 'app';
+export const DYNAMIC_TOKEN = ` + "`${prefix}:dynamic`" + `;
+let MUTABLE_TOKEN = 'MUTABLE';
+export const { OPTIONS_TOKEN } = builder;
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	declarations, err := parser.ParseTokenDeclarations(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get token declarations: %v", err)
+	}
+
+	// Consts without a string value are declared without a key
+	expected := map[string]string{
+		"MAILER_TOKEN":  "'MAILER'",
+		"CACHE_TOKEN":   "'CACHE'",
+		"EVENTS_TOKEN":  "",
+		"prefix":        "'app'",
+		"DYNAMIC_TOKEN": "",
+	}
+	if !reflect.DeepEqual(declarations, expected) {
+		t.Errorf("Expected declarations %v, got %v", expected, declarations)
+	}
+}
+
+func TestParseReExports(t *testing.T) {
+	sourceCode := `
+export { MAILER_TOKEN, CACHE_TOKEN as CACHE } from './mailer.tokens';
+export * from './events.tokens';
+export * as queues from './queue.tokens';
+export { EVENTS_TOKEN };
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	reExports, err := parser.ParseReExports(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get re-exports: %v", err)
+	}
+
+	expected := map[string][]string{
+		"MAILER_TOKEN": {"./mailer.tokens"},
+		"*":            {"./events.tokens"},
+	}
+	if !reflect.DeepEqual(reExports, expected) {
+		t.Errorf("Expected re-exports %v, got %v", expected, reExports)
+	}
+}
+
+func TestParseInjectedTokens(t *testing.T) {
+	sourceCode := `
+import { Inject, Injectable } from "@nestjs/common";
+import { MAILER_TOKEN } from "./tokens";
+@Injectable()
+export class NotificationService {
+  constructor(
+    @Inject(MAILER_TOKEN) private readonly mailer: Mailer,
+    @Inject("CACHE") private readonly cache: Cache,
+    private readonly users: UsersService,
+  ) {}
+}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	tokens, err := parser.ParseInjectedTokens(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get injected tokens: %v", err)
+	}
+
+	expected := []string{"MAILER_TOKEN", "'CACHE'"}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Expected tokens %v, got %v", expected, tokens)
+	}
+}
+
+func TestParseModuleTokenProviders(t *testing.T) {
+	sourceCode := `
+import { Module } from "@nestjs/common";
+@Module({
+  providers: [
+    MailerService,
+    { provide: MAILER_TOKEN, useClass: SmtpMailer },
+    { provide: "CACHE", useFactory: (config) => config, inject: [ConfigService, { token: "OPTIONS", optional: true }] },
+    { provide: LEGACY_MAILER, useExisting: MAILER_TOKEN },
+  ],
+  exports: [MAILER_TOKEN, "CACHE"],
+})
+export class MailerModule {}
+`
+
+	node, err := sitter.ParseCtx(context.Background(), []byte(sourceCode), typescript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to parse source code: %v", err)
+	}
+
+	providers, err := parser.ParseModuleProviders(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get providers: %v", err)
+	}
+	expectedProviders := map[string][]string{"MailerModule": {"MailerService", "SmtpMailer"}}
+	if !reflect.DeepEqual(providers, expectedProviders) {
+		t.Errorf("Expected providers %v, got %v", expectedProviders, providers)
+	}
+
+	injections, err := parser.ParseModuleProviderInjections(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get provider injections: %v", err)
+	}
+	expectedInjections := map[string][]string{"MailerModule": {"ConfigService", "'OPTIONS'", "MAILER_TOKEN"}}
+	if !reflect.DeepEqual(injections, expectedInjections) {
+		t.Errorf("Expected injections %v, got %v", expectedInjections, injections)
+	}
+
+	exports, err := parser.ParseModuleExports(node, []byte(sourceCode))
+	if err != nil {
+		t.Fatalf("Failed to get exports: %v", err)
+	}
+	expectedExports := map[string][]string{"MailerModule": {"MAILER_TOKEN", "'CACHE'"}}
+	if !reflect.DeepEqual(exports, expectedExports) {
+		t.Errorf("Expected exports %v, got %v", expectedExports, exports)
+	}
+}
@@ -0,0 +1,64 @@
+package reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// checkstyleVersion is the Checkstyle report version consumers expect
+const checkstyleVersion = "4.3"
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// formatCheckstyle formats results as Checkstyle XML, with a file element for
+// each module file and an error element for each finding. The source of an
+// error is the rule ID.
+func (f *Formatter) formatCheckstyle(results []*analysis.ModuleAnalysisResult) (string, error) {
+	report := checkstyleReport{Version: checkstyleVersion}
+	files, byFile := resultsByFile(results)
+	for _, file := range files {
+		checkstyleFile := checkstyleFile{Name: filepath.ToSlash(file)}
+		for _, result := range byFile[file] {
+			for _, finding := range result.AllFindings() {
+				rule, _ := analysis.RuleByID(finding.RuleID)
+				checkstyleError := checkstyleError{
+					Severity: rule.Severity,
+					Message:  finding.Message,
+					Source:   finding.RuleID,
+				}
+				if finding.Location != nil {
+					checkstyleError.Line = finding.Location.StartLine
+					checkstyleError.Column = finding.Location.StartColumn
+				}
+				checkstyleFile.Errors = append(checkstyleFile.Errors, checkstyleError)
+			}
+		}
+		report.Files = append(report.Files, checkstyleFile)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Checkstyle XML: %w", err)
+	}
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,26 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func TestFormatter_Format_Checkstyle(t *testing.T) {
+	output, err := reporting.NewFormatter().Format(xmlReportResults(), reporting.FormatCheckstyle)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="src/app.module.ts">
+    <error line="5" column="13" severity="warning" message="UnusedModule is imported but no provider or controller of AppModule uses anything it exports" source="unused-import"></error>
+    <error line="8" column="14" severity="error" message="UsersService is injected but &lt;UsersModule&gt; is not imported" source="missing-import"></error>
+  </file>
+  <file name="src/users/users.module.ts"></file>
+</checkstyle>`
+	if output != expected {
+		t.Errorf("Output mismatch\nGot:\n%s\n\nExpected:\n%s", output, expected)
+	}
+}
@@ -0,0 +1,35 @@
+package reporting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// fingerprinter identifies findings across runs. A fingerprint covers the
+// file, module, rule and name of a finding but not its location, so it stays
+// the same when code moves lines. Repeated findings are told apart by their
+// order.
+type fingerprinter struct {
+	seen map[string]int
+}
+
+func newFingerprinter() *fingerprinter {
+	return &fingerprinter{seen: make(map[string]int)}
+}
+
+// fingerprint returns the fingerprint of the next finding of a module
+func (p *fingerprinter) fingerprint(result *analysis.ModuleAnalysisResult, finding analysis.Finding) string {
+	key := strings.Join([]string{filepath.ToSlash(result.FilePath), result.ModuleName, finding.RuleID, finding.Name}, "\x00")
+	occurrence := p.seen[key]
+	p.seen[key]++
+	if occurrence > 0 {
+		key += fmt.Sprintf("\x00%d", occurrence)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
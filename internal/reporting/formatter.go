@@ -1,27 +1,81 @@
 package reporting
 
 import (
-	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
 )
 
 // OutputFormat defines the format for output
 type OutputFormat string
 
 const (
-	FormatText OutputFormat = "text"
-	FormatJSON OutputFormat = "json"
+	FormatText       OutputFormat = "text"
+	FormatJSON       OutputFormat = "json"
+	FormatSARIF      OutputFormat = "sarif"
+	FormatJUnit      OutputFormat = "junit"
+	FormatCheckstyle OutputFormat = "checkstyle"
+	FormatGitHub     OutputFormat = "github"
+	FormatGitLab     OutputFormat = "gitlab"
+	FormatMarkdown   OutputFormat = "markdown"
+	FormatTemplate   OutputFormat = "template"
 )
 
+// IncludesFixes reports whether the format describes the fixes given to
+// Formatter.WithFixes
+func (format OutputFormat) IncludesFixes() bool {
+	return format == FormatJSON || format == FormatSARIF || format == FormatMarkdown || format == FormatTemplate
+}
+
+// Tool identifies the linter in reports
+const (
+	ToolName           = "nestjs-module-lint"
+	ToolInformationURI = "https://github.com/evanrichards/nestjs-module-lint"
+)
+
+// ToolVersion is the version reported as the tool's, overridden at build time
+// with -ldflags "-X github.com/evanrichards/nestjs-module-lint/internal/reporting.ToolVersion=..."
+var ToolVersion = "0.1.0"
+
 // Formatter handles formatting of analysis results
-type Formatter struct{}
+type Formatter struct {
+	// fixes holds the fix edits of findings, by fixKey
+	fixes map[string][]fixing.Edit
+	// markdownMaxSize limits the size of markdown reports in bytes
+	markdownMaxSize int
+	// template renders FormatTemplate
+	template *template.Template
+	// run describes the run in reports
+	run ReportRun
+	// verbose makes text output explain every import
+	verbose bool
+	// color highlights verbose text output with ANSI escape sequences
+	color bool
+}
 
 // NewFormatter creates a new result formatter
 func NewFormatter() *Formatter {
-	return &Formatter{}
+	return &Formatter{
+		fixes:           make(map[string][]fixing.Edit),
+		markdownMaxSize: DefaultMarkdownMaxSize,
+	}
+}
+
+// WithFixes makes the formats that describe fixes include the given ones
+func (f *Formatter) WithFixes(fixes []fixing.FindingFix) *Formatter {
+	for _, fix := range fixes {
+		f.fixes[fixKey(fix.File, fix.Module, fix.RuleID, fix.Name)] = fix.Edits
+	}
+	return f
+}
+
+// fixKey identifies the finding of a rule about name in a module
+func fixKey(file, module, ruleID, name string) string {
+	return strings.Join([]string{file, module, ruleID, name}, "\x00")
 }
 
 // Format formats the analysis results according to the specified format
@@ -31,24 +85,33 @@ func (f *Formatter) Format(results []*analysis.ModuleAnalysisResult, format Outp
 		return f.formatJSON(results)
 	case FormatText:
 		return f.formatText(results), nil
+	case FormatSARIF:
+		return f.formatSARIF(results)
+	case FormatJUnit:
+		return f.formatJUnit(results)
+	case FormatCheckstyle:
+		return f.formatCheckstyle(results)
+	case FormatGitHub:
+		return f.formatGitHub(results), nil
+	case FormatGitLab:
+		return f.formatGitLab(results)
+	case FormatMarkdown:
+		return f.formatMarkdown(results), nil
+	case FormatTemplate:
+		return f.formatTemplate(results)
 	default:
 		return "", fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
-// formatJSON formats results as JSON
-func (f *Formatter) formatJSON(results []*analysis.ModuleAnalysisResult) (string, error) {
-	data, err := json.Marshal(results)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-	return string(data), nil
-}
-
 // formatText formats results as human-readable text
 func (f *Formatter) formatText(results []*analysis.ModuleAnalysisResult) string {
+	if f.verbose {
+		return f.formatVerboseText(results)
+	}
+	results = withFindings(results)
 	if len(results) == 0 {
-		return "No unused imports found."
+		return f.GetSummary(results, false)
 	}
 
 	var builder strings.Builder
@@ -60,7 +123,7 @@ func (f *Formatter) formatText(results []*analysis.ModuleAnalysisResult) string
 		builder.WriteString(f.formatModuleResult(result))
 	}
 
-	builder.WriteString(fmt.Sprintf("\nTotal number of modules with unused imports: %d\n", len(results)))
+	builder.WriteString("\n" + f.GetSummary(results, false) + "\n")
 
 	return builder.String()
 }
@@ -70,44 +133,138 @@ func (f *Formatter) formatModuleResult(result *analysis.ModuleAnalysisResult) st
 	builder := strings.Builder{}
 
 	builder.WriteString(fmt.Sprintf("Module: %s\n", result.ModuleName))
-	builder.WriteString(fmt.Sprintf("Path: %s\n", result.FilePath))
+	builder.WriteString(fmt.Sprintf("Path: %s\n", FormatPath(result.FilePath, result.Location)))
 
 	if len(result.UnusedImports) > 0 {
 		builder.WriteString("Unused Imports:\n")
 		for _, imp := range result.UnusedImports {
-			builder.WriteString(fmt.Sprintf("\t%s\n", imp))
+			builder.WriteString(fmt.Sprintf("\t%s%s\n", imp, f.importLocation(result, imp)))
+		}
+	}
+
+	for _, rule := range analysis.Rules {
+		findings := result.FindingsByRule(rule.ID)
+		if len(findings) == 0 {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%s:\n", rule.Title))
+		for _, finding := range findings {
+			builder.WriteString(fmt.Sprintf("\t%s - %s%s\n", finding.Name, finding.Message, FormatLocation(finding.Location)))
 		}
 	}
 
 	if len(result.IgnoredImports) > 0 {
 		builder.WriteString("Ignored Imports:\n")
 		for _, imp := range result.IgnoredImports {
-			builder.WriteString(fmt.Sprintf("\t%s (ignored)\n", imp))
+			builder.WriteString(fmt.Sprintf("\t%s (ignored)%s\n", imp, f.importLocation(result, imp)))
 		}
 	}
 
 	if len(result.ReExportedImports) > 0 {
 		builder.WriteString("Re-exported Imports:\n")
 		for _, imp := range result.ReExportedImports {
-			builder.WriteString(fmt.Sprintf("\t%s (re-exported)\n", imp))
+			builder.WriteString(fmt.Sprintf("\t%s (re-exported)%s\n", imp, f.importLocation(result, imp)))
 		}
 	}
 
 	return builder.String()
 }
 
-// GetSummary returns a summary message for check mode
+// withFindings returns the results of the modules with findings, leaving out
+// clean modules
+func withFindings(results []*analysis.ModuleAnalysisResult) []*analysis.ModuleAnalysisResult {
+	filtered := make([]*analysis.ModuleAnalysisResult, 0, len(results))
+	for _, result := range results {
+		if result.HasFindings() {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// resultsByFile groups results by file, sorting the files by path and the
+// modules of a file by name
+func resultsByFile(results []*analysis.ModuleAnalysisResult) ([]string, map[string][]*analysis.ModuleAnalysisResult) {
+	byFile := make(map[string][]*analysis.ModuleAnalysisResult)
+	for _, result := range results {
+		byFile[result.FilePath] = append(byFile[result.FilePath], result)
+	}
+	files := make([]string, 0, len(byFile))
+	for file, fileResults := range byFile {
+		files = append(files, file)
+		sort.SliceStable(fileResults, func(i, j int) bool {
+			return fileResults[i].ModuleName < fileResults[j].ModuleName
+		})
+	}
+	sort.Strings(files)
+	return files, byFile
+}
+
+// importLocation formats the location of an imports element, if known
+func (f *Formatter) importLocation(result *analysis.ModuleAnalysisResult, name string) string {
+	location, ok := result.ImportLocations[name]
+	if !ok {
+		return ""
+	}
+	return FormatLocation(&location)
+}
+
+// FormatPath returns a path followed by the line and column a location
+// starts at, the form editors and terminals link to
+func FormatPath(path string, location *analysis.Location) string {
+	if location == nil {
+		return path
+	}
+	return fmt.Sprintf("%s:%d:%d", path, location.StartLine, location.StartColumn)
+}
+
+// FormatLocation returns where a location starts for text output, e.g.
+// " (line 6, column 13)", or an empty string without a location
+func FormatLocation(location *analysis.Location) string {
+	if location == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (line %d, column %d)", location.StartLine, location.StartColumn)
+}
+
+// Pluralize returns a count followed by a noun, adding an s unless the count
+// is one, e.g. "1 file" or "2 files"
+func Pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// GetSummary returns the closing line of text output: the number of modules
+// with findings and the findings of each rule, or in check mode a pass or
+// fail line
 func (f *Formatter) GetSummary(results []*analysis.ModuleAnalysisResult, checkMode bool) string {
+	results = withFindings(results)
 	if len(results) == 0 {
 		if checkMode {
-			return "✓ No unused imports found"
+			return "✓ No findings"
 		}
-		return "No unused imports found."
+		return "No findings."
 	}
 
-	if checkMode {
-		return fmt.Sprintf("✗ Found %d modules with unused imports", len(results))
+	total := 0
+	counts := make(map[string]int)
+	for _, result := range results {
+		for _, finding := range result.AllFindings() {
+			counts[finding.RuleID]++
+			total++
+		}
+	}
+	var byRule []string
+	for _, rule := range analysis.Rules {
+		if counts[rule.ID] > 0 {
+			byRule = append(byRule, fmt.Sprintf("%s: %d", rule.ID, counts[rule.ID]))
+		}
 	}
 
-	return fmt.Sprintf("Total number of modules with unused imports: %d", len(results))
+	if checkMode {
+		return fmt.Sprintf("✗ Found %s in %s (%s)", Pluralize(total, "finding"), Pluralize(len(results), "module"), strings.Join(byRule, ", "))
+	}
+	return fmt.Sprintf("Total number of modules with findings: %d (%s)", len(results), strings.Join(byRule, ", "))
 }
@@ -31,14 +31,21 @@ func TestFormatter_Format_JSON(t *testing.T) {
 	}
 
 	// Verify it's valid JSON
-	var parsed []analysis.ModuleAnalysisResult
+	var parsed struct {
+		SchemaVersion string            `json:"schema_version"`
+		Findings      []json.RawMessage `json:"findings"`
+		Modules       []json.RawMessage `json:"modules"`
+	}
 	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
 		t.Errorf("Output is not valid JSON: %v", err)
 	}
 
 	// Verify content
-	if len(parsed) != 2 {
-		t.Errorf("Expected 2 results in JSON, got %d", len(parsed))
+	if parsed.SchemaVersion != reporting.JSONSchemaVersion {
+		t.Errorf("Expected schema version %s, got %q", reporting.JSONSchemaVersion, parsed.SchemaVersion)
+	}
+	if len(parsed.Modules) != 2 || len(parsed.Findings) != 3 {
+		t.Errorf("Expected 2 modules with 3 findings in JSON, got %d and %d", len(parsed.Modules), len(parsed.Findings))
 	}
 }
 
@@ -71,7 +78,7 @@ func TestFormatter_Format_Text(t *testing.T) {
 		"IgnoredModule (ignored)",
 		"Re-exported Imports:",
 		"ReExportedModule (re-exported)",
-		"Total number of modules with unused imports: 1",
+		"Total number of modules with findings: 1 (unused-import: 2)",
 	}
 
 	for _, expected := range expectedStrings {
@@ -81,6 +88,44 @@ func TestFormatter_Format_Text(t *testing.T) {
 	}
 }
 
+func TestFormatter_Format_TextLocations(t *testing.T) {
+	formatter := reporting.NewFormatter()
+
+	results := []*analysis.ModuleAnalysisResult{
+		{
+			ModuleName:    "AppModule",
+			FilePath:      "src/app.module.ts",
+			UnusedImports: []string{"UnusedModule"},
+			Location:      &analysis.Location{StartLine: 8, StartColumn: 14, EndLine: 8, EndColumn: 23},
+			ImportLocations: map[string]analysis.Location{
+				"UnusedModule": {StartLine: 5, StartColumn: 13, EndLine: 5, EndColumn: 25},
+			},
+			Findings: []analysis.Finding{{
+				RuleID:   analysis.RuleUnusedFeatureEntity,
+				Name:     "Order",
+				Message:  "Order is registered with TypeOrmModule.forFeature() but no provider injects it",
+				Location: &analysis.Location{StartLine: 5, StartColumn: 53, EndLine: 5, EndColumn: 58},
+			}},
+		},
+	}
+
+	output, err := formatter.Format(results, reporting.FormatText)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expectedStrings := []string{
+		"Path: src/app.module.ts:8:14\n",
+		"\tUnusedModule (line 5, column 13)\n",
+		"no provider injects it (line 5, column 53)\n",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
 func TestFormatter_Format_EmptyResults(t *testing.T) {
 	formatter := reporting.NewFormatter()
 
@@ -92,8 +137,8 @@ func TestFormatter_Format_EmptyResults(t *testing.T) {
 		t.Fatalf("Format failed: %v", err)
 	}
 
-	if output != "No unused imports found." {
-		t.Errorf("Expected 'No unused imports found.', got '%s'", output)
+	if output != "No findings." {
+		t.Errorf("Expected 'No findings.', got '%s'", output)
 	}
 
 	// Test JSON format
@@ -102,8 +147,15 @@ func TestFormatter_Format_EmptyResults(t *testing.T) {
 		t.Fatalf("Format failed: %v", err)
 	}
 
-	if output != "[]" {
-		t.Errorf("Expected empty JSON array, got '%s'", output)
+	var parsed struct {
+		Findings []json.RawMessage `json:"findings"`
+		Modules  []json.RawMessage `json:"modules"`
+	}
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if parsed.Findings == nil || len(parsed.Findings) != 0 || parsed.Modules == nil || len(parsed.Modules) != 0 {
+		t.Errorf("Expected empty findings and modules arrays, got '%s'", output)
 	}
 }
 
@@ -131,22 +183,23 @@ func TestFormatter_GetSummary(t *testing.T) {
 			name:      "empty results with check mode",
 			results:   []*analysis.ModuleAnalysisResult{},
 			checkMode: true,
-			expected:  "✓ No unused imports found",
+			expected:  "✓ No findings",
 		},
 		{
 			name:      "empty results without check mode",
 			results:   []*analysis.ModuleAnalysisResult{},
 			checkMode: false,
-			expected:  "No unused imports found.",
+			expected:  "No findings.",
 		},
 		{
 			name: "results with check mode",
 			results: []*analysis.ModuleAnalysisResult{
 				{ModuleName: "Module1", UnusedImports: []string{"Import1"}},
-				{ModuleName: "Module2", UnusedImports: []string{"Import2"}},
+				{ModuleName: "Module2", UnusedImports: []string{"Import2"}, Findings: []analysis.Finding{{RuleID: analysis.RuleMissingImport, Name: "UsersService"}}},
+				{ModuleName: "Module3"},
 			},
 			checkMode: true,
-			expected:  "✗ Found 2 modules with unused imports",
+			expected:  "✗ Found 3 findings in 2 modules (unused-import: 2, missing-import: 1)",
 		},
 		{
 			name: "results without check mode",
@@ -154,7 +207,7 @@ func TestFormatter_GetSummary(t *testing.T) {
 				{ModuleName: "Module1", UnusedImports: []string{"Import1"}},
 			},
 			checkMode: false,
-			expected:  "Total number of modules with unused imports: 1",
+			expected:  "Total number of modules with findings: 1 (unused-import: 1)",
 		},
 	}
 
@@ -0,0 +1,45 @@
+package reporting
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// formatGitHub formats results as GitHub Actions workflow commands, one
+// ::error or ::warning annotation per finding
+func (f *Formatter) formatGitHub(results []*analysis.ModuleAnalysisResult) string {
+	var builder strings.Builder
+	files, byFile := resultsByFile(results)
+	for _, file := range files {
+		for _, result := range byFile[file] {
+			for _, finding := range result.AllFindings() {
+				rule, _ := analysis.RuleByID(finding.RuleID)
+				properties := []string{"file=" + escapeGitHubProperty(filepath.ToSlash(file))}
+				if location := finding.Location; location != nil {
+					properties = append(properties,
+						fmt.Sprintf("line=%d", location.StartLine),
+						fmt.Sprintf("endLine=%d", location.EndLine),
+						fmt.Sprintf("col=%d", location.StartColumn),
+						fmt.Sprintf("endColumn=%d", location.EndColumn),
+					)
+				}
+				properties = append(properties, "title="+escapeGitHubProperty(fmt.Sprintf("%s (%s)", rule.Title, ToolName)))
+				builder.WriteString(fmt.Sprintf("::%s %s::%s\n", rule.Severity, strings.Join(properties, ","), escapeGitHubData(finding.Message)))
+			}
+		}
+	}
+	return builder.String()
+}
+
+// escapeGitHubData escapes the message of a workflow command
+func escapeGitHubData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(value string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeGitHubData(value))
+}
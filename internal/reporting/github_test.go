@@ -0,0 +1,33 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func TestFormatter_Format_GitHub(t *testing.T) {
+	results := append(xmlReportResults(), &analysis.ModuleAnalysisResult{
+		ModuleName: "JobsModule",
+		FilePath:   "src/jobs,v2/jobs.module.ts",
+		Findings: []analysis.Finding{{
+			RuleID:  analysis.RuleUnusedQueue,
+			Name:    "emails",
+			Message: "Queue emails is registered but 100% unused\nsee docs",
+		}},
+	})
+
+	output, err := reporting.NewFormatter().Format(results, reporting.FormatGitHub)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := `::warning file=src/app.module.ts,line=5,endLine=0,col=13,endColumn=0,title=Unused Imports (nestjs-module-lint)::UnusedModule is imported but no provider or controller of AppModule uses anything it exports
+::error file=src/app.module.ts,line=8,endLine=0,col=14,endColumn=0,title=Missing Imports (nestjs-module-lint)::UsersService is injected but <UsersModule> is not imported
+::warning file=src/jobs%2Cv2/jobs.module.ts,title=Unused Queues (nestjs-module-lint)::Queue emails is registered but 100%25 unused%0Asee docs
+`
+	if output != expected {
+		t.Errorf("Output mismatch\nGot:\n%s\n\nExpected:\n%s", output, expected)
+	}
+}
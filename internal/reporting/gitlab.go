@@ -0,0 +1,65 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// gitLabSeverities maps rule severities to Code Quality severities
+var gitLabSeverities = map[string]string{
+	analysis.SeverityError:   "major",
+	analysis.SeverityWarning: "minor",
+}
+
+type gitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitLabLocation `json:"location"`
+}
+
+type gitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitLabLines `json:"lines"`
+}
+
+type gitLabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// formatGitLab formats results as a GitLab Code Quality report. Fingerprints
+// leave out line numbers, so moved code keeps its issues.
+func (f *Formatter) formatGitLab(results []*analysis.ModuleAnalysisResult) (string, error) {
+	issues := make([]gitLabIssue, 0)
+	fingerprints := newFingerprinter()
+	files, byFile := resultsByFile(results)
+	for _, file := range files {
+		for _, result := range byFile[file] {
+			for _, finding := range result.AllFindings() {
+				rule, _ := analysis.RuleByID(finding.RuleID)
+				issue := gitLabIssue{
+					Description: finding.Message,
+					CheckName:   finding.RuleID,
+					Fingerprint: fingerprints.fingerprint(result, finding),
+					Severity:    gitLabSeverities[rule.Severity],
+					Location:    gitLabLocation{Path: filepath.ToSlash(file), Lines: gitLabLines{Begin: 1, End: 1}},
+				}
+				if location := finding.Location; location != nil {
+					issue.Location.Lines = gitLabLines{Begin: location.StartLine, End: location.EndLine}
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Code Quality report: %w", err)
+	}
+	return string(data), nil
+}
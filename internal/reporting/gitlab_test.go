@@ -0,0 +1,69 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+type gitLabIssue struct {
+	CheckName   string `json:"check_name"`
+	Fingerprint string `json:"fingerprint"`
+	Severity    string `json:"severity"`
+	Location    struct {
+		Path  string `json:"path"`
+		Lines struct {
+			Begin int `json:"begin"`
+		} `json:"lines"`
+	} `json:"location"`
+}
+
+func formatGitLab(t *testing.T, results []*analysis.ModuleAnalysisResult) []gitLabIssue {
+	t.Helper()
+	output, err := reporting.NewFormatter().Format(results, reporting.FormatGitLab)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	var issues []gitLabIssue
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	return issues
+}
+
+func TestFormatter_Format_GitLab(t *testing.T) {
+	issues := formatGitLab(t, xmlReportResults())
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %v", issues)
+	}
+	if issues[0].CheckName != analysis.RuleUnusedImport || issues[0].Severity != "minor" || issues[0].Location.Path != "src/app.module.ts" || issues[0].Location.Lines.Begin != 5 {
+		t.Errorf("Unexpected unused import issue %+v", issues[0])
+	}
+	if issues[1].CheckName != analysis.RuleMissingImport || issues[1].Severity != "major" {
+		t.Errorf("Unexpected missing import issue %+v", issues[1])
+	}
+	if issues[0].Fingerprint == "" || issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Errorf("Expected distinct fingerprints, got %q and %q", issues[0].Fingerprint, issues[1].Fingerprint)
+	}
+
+	// Code moving lines keeps the fingerprints
+	shifted := xmlReportResults()
+	shifted[1].ImportLocations["UnusedModule"] = analysis.Location{StartLine: 12, StartColumn: 13}
+	shifted[1].Findings[0].Location = &analysis.Location{StartLine: 15, StartColumn: 14}
+	shiftedIssues := formatGitLab(t, shifted)
+	for i := range issues {
+		if shiftedIssues[i].Fingerprint != issues[i].Fingerprint {
+			t.Errorf("Expected fingerprint %q to survive a line shift, got %q", issues[i].Fingerprint, shiftedIssues[i].Fingerprint)
+		}
+	}
+
+	// A repeated finding gets a fingerprint of its own
+	repeated := xmlReportResults()
+	repeated[1].UnusedImports = []string{"UnusedModule", "UnusedModule"}
+	repeatedIssues := formatGitLab(t, repeated)
+	if repeatedIssues[0].Fingerprint != issues[0].Fingerprint || repeatedIssues[1].Fingerprint == repeatedIssues[0].Fingerprint {
+		t.Errorf("Expected unique fingerprints for repeated findings, got %v", repeatedIssues)
+	}
+}
@@ -0,0 +1,279 @@
+package reporting
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// htmlTemplate is the page of HTML reports, with its styles and script
+// inline so it opens from disk without network access
+//
+//go:embed report.html
+var htmlTemplate string
+
+// htmlDataPlaceholder marks where the report data goes in htmlTemplate
+const htmlDataPlaceholder = "/*REPORT_DATA*/"
+
+// htmlReport is the data the HTML report's script renders
+type htmlReport struct {
+	Tool    string     `json:"tool"`
+	Version string     `json:"version"`
+	Nodes   []htmlNode `json:"nodes"`
+	Edges   []htmlEdge `json:"edges"`
+	Stats   htmlStats  `json:"stats"`
+}
+
+// htmlNode is a module of the graph. Modules imported from packages, or from
+// files that were not analyzed, are nodes without metadata.
+type htmlNode struct {
+	Name       string        `json:"name"`
+	File       string        `json:"file,omitempty"`
+	Line       int           `json:"line,omitempty"`
+	Analyzed   bool          `json:"analyzed"`
+	External   bool          `json:"external"`
+	Imports    []string      `json:"imports"`
+	Providers  []string      `json:"providers"`
+	Exports    []string      `json:"exports"`
+	Unused     []string      `json:"unused"`
+	Ignored    []string      `json:"ignored"`
+	ReExported []string      `json:"reexported"`
+	Findings   []htmlFinding `json:"findings"`
+	// Layer is the length of the longest import chain below the module,
+	// leaving out the edges of cycles
+	Layer int  `json:"layer"`
+	Cycle bool `json:"cycle"`
+}
+
+type htmlFinding struct {
+	RuleID   string `json:"rule_id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// htmlEdge is an import of one node by another, by node index
+type htmlEdge struct {
+	From       int    `json:"from"`
+	To         int    `json:"to"`
+	Name       string `json:"name"`
+	Unused     bool   `json:"unused"`
+	Ignored    bool   `json:"ignored"`
+	ReExported bool   `json:"reexported"`
+	Cycle      bool   `json:"cycle"`
+}
+
+type htmlStats struct {
+	Modules     int `json:"modules"`
+	Findings    int `json:"findings"`
+	UnusedEdges int `json:"unused_edges"`
+	CycleEdges  int `json:"cycle_edges"`
+}
+
+// HTML formats results as a self-contained HTML page drawing the module
+// graph. Results need the metadata of AnalysisOptions.IncludeModuleMetadata
+// for the graph to have edges.
+func (f *Formatter) HTML(results []*analysis.ModuleAnalysisResult) (string, error) {
+	report := buildHTMLReport(results)
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report data: %w", err)
+	}
+	// json.Marshal escapes <, > and &, so the data cannot close its script
+	return strings.Replace(htmlTemplate, htmlDataPlaceholder, string(data), 1), nil
+}
+
+// buildHTMLReport builds the module graph of results
+func buildHTMLReport(results []*analysis.ModuleAnalysisResult) *htmlReport {
+	report := &htmlReport{Tool: ToolName, Version: ToolVersion, Nodes: []htmlNode{}, Edges: []htmlEdge{}}
+	indexes := make(map[string]int)
+	nodeIndex := func(file, name string) int {
+		key := filepath.ToSlash(file) + "\x00" + name
+		if index, ok := indexes[key]; ok {
+			return index
+		}
+		indexes[key] = len(report.Nodes)
+		report.Nodes = append(report.Nodes, htmlNode{
+			Name:     name,
+			File:     filepath.ToSlash(file),
+			External: file == "",
+		})
+		return indexes[key]
+	}
+
+	files, byFile := resultsByFile(results)
+	var sorted []*analysis.ModuleAnalysisResult
+	for _, file := range files {
+		for _, result := range byFile[file] {
+			sorted = append(sorted, result)
+			nodeIndex(result.FilePath, result.ModuleName)
+		}
+	}
+
+	for _, result := range sorted {
+		index := nodeIndex(result.FilePath, result.ModuleName)
+		node := &report.Nodes[index]
+		node.Analyzed = true
+		node.Unused = result.UnusedImports
+		node.Ignored = result.IgnoredImports
+		node.ReExported = result.ReExportedImports
+		if result.Location != nil {
+			node.Line = result.Location.StartLine
+		}
+		for _, finding := range result.AllFindings() {
+			rule, _ := analysis.RuleByID(finding.RuleID)
+			htmlFinding := htmlFinding{RuleID: rule.ID, Title: rule.Title, Severity: rule.Severity, Message: finding.Message}
+			if finding.Location != nil {
+				htmlFinding.Line = finding.Location.StartLine
+			}
+			node.Findings = append(node.Findings, htmlFinding)
+		}
+		report.Stats.Modules++
+		report.Stats.Findings += len(node.Findings)
+
+		if result.Metadata == nil {
+			continue
+		}
+		node.Imports = result.Metadata.Imports
+		node.Providers = result.Metadata.Providers
+		node.Exports = result.Metadata.Exports
+		for _, name := range result.Metadata.Imports {
+			edge := htmlEdge{
+				From:       index,
+				Name:       name,
+				Unused:     slices.Contains(result.UnusedImports, name),
+				Ignored:    slices.Contains(result.IgnoredImports, name),
+				ReExported: slices.Contains(result.ReExportedImports, name),
+			}
+			// nodeIndex can grow the nodes, so node is not used past here
+			edge.To = nodeIndex(result.Metadata.ImportFiles[name], name)
+			report.Edges = append(report.Edges, edge)
+		}
+	}
+
+	markCycles(report)
+	layerNodes(report)
+	for i := range report.Nodes {
+		node := &report.Nodes[i]
+		for _, list := range []*[]string{&node.Imports, &node.Providers, &node.Exports, &node.Unused, &node.Ignored, &node.ReExported} {
+			if *list == nil {
+				*list = []string{}
+			}
+		}
+		if node.Findings == nil {
+			node.Findings = []htmlFinding{}
+		}
+	}
+	for _, edge := range report.Edges {
+		if edge.Unused {
+			report.Stats.UnusedEdges++
+		}
+		if edge.Cycle {
+			report.Stats.CycleEdges++
+		}
+	}
+	return report
+}
+
+// markCycles marks the nodes and edges of import cycles, using Tarjan's
+// strongly connected components
+func markCycles(report *htmlReport) {
+	adjacent := make([][]int, len(report.Nodes))
+	for _, edge := range report.Edges {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+	}
+
+	component := make([]int, len(report.Nodes))
+	order := make([]int, len(report.Nodes))
+	low := make([]int, len(report.Nodes))
+	onStack := make([]bool, len(report.Nodes))
+	var stack []int
+	sizes := []int{}
+	counter := 0
+	for i := range order {
+		order[i] = -1
+	}
+
+	var visit func(node int)
+	visit = func(node int) {
+		order[node] = counter
+		low[node] = counter
+		counter++
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, next := range adjacent[node] {
+			if order[next] == -1 {
+				visit(next)
+				low[node] = min(low[node], low[next])
+			} else if onStack[next] {
+				low[node] = min(low[node], order[next])
+			}
+		}
+		if low[node] != order[node] {
+			return
+		}
+		size := 0
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component[top] = len(sizes)
+			size++
+			if top == node {
+				break
+			}
+		}
+		sizes = append(sizes, size)
+	}
+	for node := range report.Nodes {
+		if order[node] == -1 {
+			visit(node)
+		}
+	}
+
+	for i, edge := range report.Edges {
+		if component[edge.From] != component[edge.To] {
+			continue
+		}
+		if edge.From == edge.To || sizes[component[edge.From]] > 1 {
+			report.Edges[i].Cycle = true
+			report.Nodes[edge.From].Cycle = true
+			report.Nodes[edge.To].Cycle = true
+		}
+	}
+}
+
+// layerNodes sets the layer of each node. Leaving out the edges of cycles
+// makes the graph acyclic, so the longest chains are finite.
+func layerNodes(report *htmlReport) {
+	adjacent := make([][]int, len(report.Nodes))
+	for _, edge := range report.Edges {
+		if !edge.Cycle {
+			adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+		}
+	}
+
+	done := make([]bool, len(report.Nodes))
+	var layer func(node int) int
+	layer = func(node int) int {
+		if done[node] {
+			return report.Nodes[node].Layer
+		}
+		deepest := 0
+		for _, next := range adjacent[node] {
+			deepest = max(deepest, layer(next)+1)
+		}
+		report.Nodes[node].Layer = deepest
+		done[node] = true
+		return deepest
+	}
+	for node := range report.Nodes {
+		layer(node)
+	}
+}
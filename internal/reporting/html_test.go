@@ -0,0 +1,147 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func TestFormatter_HTML(t *testing.T) {
+	results := []*analysis.ModuleAnalysisResult{
+		{
+			ModuleName:    "AppModule",
+			FilePath:      "src/app.module.ts",
+			UnusedImports: []string{"OrdersModule"},
+			Findings: []analysis.Finding{{
+				RuleID:  analysis.RuleMissingImport,
+				Name:    "MailService",
+				Message: "MailService is injected but </script><MailModule> is not imported",
+			}},
+			Metadata: &analysis.ModuleMetadata{
+				Imports:   []string{"ConfigModule", "UsersModule", "OrdersModule"},
+				Providers: []string{"AppService"},
+				ImportFiles: map[string]string{
+					"UsersModule":  "src/users/users.module.ts",
+					"OrdersModule": "src/orders/orders.module.ts",
+				},
+			},
+		},
+		{
+			ModuleName: "UsersModule",
+			FilePath:   "src/users/users.module.ts",
+			Metadata: &analysis.ModuleMetadata{
+				Imports:     []string{"OrdersModule"},
+				Exports:     []string{"UsersService"},
+				ImportFiles: map[string]string{"OrdersModule": "src/orders/orders.module.ts"},
+			},
+		},
+		{
+			ModuleName: "OrdersModule",
+			FilePath:   "src/orders/orders.module.ts",
+			Metadata: &analysis.ModuleMetadata{
+				Imports:     []string{"UsersModule"},
+				ImportFiles: map[string]string{"UsersModule": "src/users/users.module.ts"},
+			},
+		},
+	}
+
+	page, err := reporting.NewFormatter().HTML(results)
+	if err != nil {
+		t.Fatalf("HTML failed: %v", err)
+	}
+
+	// The page must open without network access
+	if regexp.MustCompile(`(src|href)="https?:`).MatchString(page) {
+		t.Errorf("Expected no external resources")
+	}
+	if strings.Count(page, "</script>") != 2 {
+		t.Errorf("Expected the data not to close its script element")
+	}
+
+	match := regexp.MustCompile(`(?s)<script id="report-data" type="application/json">(.*?)</script>`).FindStringSubmatch(page)
+	if match == nil {
+		t.Fatalf("Expected the report data in the page")
+	}
+	var report struct {
+		Nodes []struct {
+			Name     string `json:"name"`
+			File     string `json:"file"`
+			Analyzed bool   `json:"analyzed"`
+			External bool   `json:"external"`
+			Findings []struct {
+				Message string `json:"message"`
+			} `json:"findings"`
+			Layer int  `json:"layer"`
+			Cycle bool `json:"cycle"`
+		} `json:"nodes"`
+		Edges []struct {
+			From   int    `json:"from"`
+			To     int    `json:"to"`
+			Name   string `json:"name"`
+			Unused bool   `json:"unused"`
+			Cycle  bool   `json:"cycle"`
+		} `json:"edges"`
+		Stats struct {
+			Modules     int `json:"modules"`
+			Findings    int `json:"findings"`
+			UnusedEdges int `json:"unused_edges"`
+			CycleEdges  int `json:"cycle_edges"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(match[1]), &report); err != nil {
+		t.Fatalf("Report data is not valid JSON: %v", err)
+	}
+
+	// Nodes follow the files in order, then the modules only imported
+	names := make([]string, len(report.Nodes))
+	for i, node := range report.Nodes {
+		names[i] = node.Name
+	}
+	if strings.Join(names, ",") != "AppModule,OrdersModule,UsersModule,ConfigModule" {
+		t.Fatalf("Unexpected nodes %v", names)
+	}
+	if config := report.Nodes[3]; config.Analyzed || !config.External || config.File != "" {
+		t.Errorf("Expected ConfigModule to be an external node, got %+v", config)
+	}
+	if report.Nodes[0].Cycle || !report.Nodes[1].Cycle || !report.Nodes[2].Cycle {
+		t.Errorf("Expected OrdersModule and UsersModule in a cycle, got %+v", report.Nodes)
+	}
+	if report.Nodes[0].Layer != 1 || report.Nodes[1].Layer != 0 || report.Nodes[3].Layer != 0 {
+		t.Errorf("Expected AppModule above the modules it imports, got %+v", report.Nodes)
+	}
+	if message := report.Nodes[0].Findings[1].Message; !strings.Contains(message, "</script><MailModule>") {
+		t.Errorf("Expected the finding message to survive escaping, got %q", message)
+	}
+
+	type edge struct {
+		from, to      string
+		unused, cycle bool
+	}
+	var edges []edge
+	for _, e := range report.Edges {
+		edges = append(edges, edge{names[e.From], names[e.To], e.Unused, e.Cycle})
+	}
+	expectedEdges := []edge{
+		{"AppModule", "ConfigModule", false, false},
+		{"AppModule", "UsersModule", false, false},
+		{"AppModule", "OrdersModule", true, false},
+		{"OrdersModule", "UsersModule", false, true},
+		{"UsersModule", "OrdersModule", false, true},
+	}
+	if len(edges) != len(expectedEdges) {
+		t.Fatalf("Expected edges %v, got %v", expectedEdges, edges)
+	}
+	for i := range edges {
+		if edges[i] != expectedEdges[i] {
+			t.Errorf("Edge %d: expected %v, got %v", i, expectedEdges[i], edges[i])
+		}
+	}
+
+	if report.Stats.Modules != 3 || report.Stats.Findings != 2 || report.Stats.UnusedEdges != 1 || report.Stats.CycleEdges != 2 {
+		t.Errorf("Unexpected stats %+v", report.Stats)
+	}
+}
@@ -0,0 +1,54 @@
+package reporting
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// JSONSchemaVersion is the version of the JSON report. The minor version
+// grows when fields are added; the major version changes when fields are
+// renamed or removed, so consumers can pin it.
+const JSONSchemaVersion = "1.0"
+
+// jsonSchema is the JSON Schema document of JSON reports
+//
+//go:embed report.schema.json
+var jsonSchema string
+
+// JSONSchema returns the JSON Schema document that JSON reports follow
+func JSONSchema() string {
+	return jsonSchema
+}
+
+// jsonReport is the envelope of JSON reports
+type jsonReport struct {
+	SchemaVersion string          `json:"schema_version"`
+	Tool          ReportTool      `json:"tool"`
+	Run           ReportRun       `json:"run"`
+	Summary       ReportStats     `json:"summary"`
+	Rules         []ReportRule    `json:"rules"`
+	Findings      []ReportFinding `json:"findings"`
+	Modules       []ReportModule  `json:"modules"`
+}
+
+// formatJSON formats results as a JSON report with every module, finding
+// and its fix
+func (f *Formatter) formatJSON(results []*analysis.ModuleAnalysisResult) (string, error) {
+	report := f.BuildReport(results)
+	data, err := json.Marshal(jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Tool:          report.Tool,
+		Run:           report.Run,
+		Summary:       report.Stats,
+		Rules:         report.Rules,
+		Findings:      report.Findings,
+		Modules:       report.Modules,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
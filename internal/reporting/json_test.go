@@ -0,0 +1,216 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func TestFormatter_Format_JSON_Envelope(t *testing.T) {
+	location := &analysis.Location{StartLine: 5, StartColumn: 13, EndLine: 5, EndColumn: 25, StartByte: 112, EndByte: 124}
+	results := []*analysis.ModuleAnalysisResult{
+		{
+			ModuleName:        "AppModule",
+			FilePath:          "src/app.module.ts",
+			UnusedImports:     []string{"UnusedModule"},
+			IgnoredImports:    []string{"LegacyModule"},
+			ReExportedImports: []string{"SharedModule"},
+			Location:          &analysis.Location{StartLine: 8, StartColumn: 14, EndLine: 8, EndColumn: 23, StartByte: 150, EndByte: 159},
+			ImportLocations:   map[string]analysis.Location{"UnusedModule": *location},
+			Findings: []analysis.Finding{{
+				RuleID:  analysis.RuleMissingImport,
+				Name:    "UsersService",
+				Message: "UsersService is injected but <UsersModule> is not imported",
+			}},
+		},
+		{ModuleName: "UsersModule", FilePath: "src/users/users.module.ts"},
+	}
+	fixes := []fixing.FindingFix{{
+		File:   "src/app.module.ts",
+		Module: "AppModule",
+		RuleID: analysis.RuleUnusedImport,
+		Name:   "UnusedModule",
+		Edits:  []fixing.Edit{{Start: 112, End: 124, Reason: "remove unused import UnusedModule"}},
+	}}
+	run := reporting.ReportRun{
+		Paths:            []string{"src/"},
+		WorkingDirectory: "/project",
+		StartedAt:        "2024-05-01T12:00:00Z",
+		DurationMS:       42,
+	}
+
+	output, err := reporting.NewFormatter().WithFixes(fixes).WithRun(run).Format(results, reporting.FormatJSON)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var report struct {
+		SchemaVersion string `json:"schema_version"`
+		Tool          struct {
+			Version string `json:"version"`
+		} `json:"tool"`
+		Run     reporting.ReportRun   `json:"run"`
+		Summary reporting.ReportStats `json:"summary"`
+		Rules   []reporting.ReportRule
+		// Findings and Modules are compared as JSON below
+		Findings []reporting.ReportFinding `json:"findings"`
+		Modules  []reporting.ReportModule  `json:"modules"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if report.SchemaVersion != reporting.JSONSchemaVersion || report.Tool.Version != reporting.ToolVersion {
+		t.Errorf("Unexpected versions %q and %q", report.SchemaVersion, report.Tool.Version)
+	}
+	if report.Run.WorkingDirectory != "/project" || report.Run.DurationMS != 42 || len(report.Run.Paths) != 1 {
+		t.Errorf("Unexpected run %+v", report.Run)
+	}
+	if report.Summary.Modules != 2 || report.Summary.ModulesWithFindings != 1 || report.Summary.Errors != 1 || report.Summary.Warnings != 1 {
+		t.Errorf("Unexpected summary %+v", report.Summary)
+	}
+	if len(report.Rules) != len(analysis.Rules) {
+		t.Errorf("Expected every rule, got %d", len(report.Rules))
+	}
+
+	if len(report.Findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %+v", report.Findings)
+	}
+	unused := report.Findings[0]
+	if unused.RuleID != analysis.RuleUnusedImport || unused.Severity != analysis.SeverityWarning || unused.Module != "AppModule" ||
+		unused.Location == nil || *unused.Location != *location || unused.Fingerprint == "" || len(unused.Fix) != 1 {
+		t.Errorf("Unexpected unused import finding %+v", unused)
+	}
+	if missing := report.Findings[1]; missing.Severity != analysis.SeverityError || missing.Fix != nil {
+		t.Errorf("Unexpected missing import finding %+v", missing)
+	}
+
+	if len(report.Modules) != 2 {
+		t.Fatalf("Expected 2 modules, got %+v", report.Modules)
+	}
+	app := report.Modules[0]
+	if strings.Join(app.IgnoredImports, ",") != "LegacyModule" || strings.Join(app.ReExportedImports, ",") != "SharedModule" || len(app.Findings) != 2 {
+		t.Errorf("Expected the module details of AppModule, got %+v", app)
+	}
+}
+
+func TestJSONSchema_MatchesReport(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(reporting.JSONSchema()), &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	fixes := []fixing.FindingFix{{
+		File:   "src/app.module.ts",
+		Module: "AppModule",
+		RuleID: analysis.RuleUnusedImport,
+		Name:   "UnusedModule",
+		Edits:  []fixing.Edit{{Start: 112, End: 124}},
+	}}
+	for _, results := range [][]*analysis.ModuleAnalysisResult{nil, xmlReportResults()} {
+		output, err := reporting.NewFormatter().WithFixes(fixes).WithRun(reporting.ReportRun{StartedAt: "2024-05-01T12:00:00Z"}).Format(results, reporting.FormatJSON)
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+		var report any
+		if err := json.Unmarshal([]byte(output), &report); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		for _, problem := range validateSchema(schema, schema, report, "$") {
+			t.Error(problem)
+		}
+	}
+}
+
+// validateSchema checks a value against the parts of JSON Schema the report
+// schema uses: type, enum, required, properties, additionalProperties, items
+// and local $ref
+func validateSchema(root, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		definition := root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			definition, _ = definition[part].(map[string]any)
+		}
+		if definition == nil {
+			return []string{fmt.Sprintf("%s: unresolved $ref %s", path, ref)}
+		}
+		return validateSchema(root, definition, value, path)
+	}
+
+	var problems []string
+	if expected, ok := schema["type"].(string); ok && jsonType(value) != expected &&
+		!(expected == "number" && jsonType(value) == "integer") {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, expected, jsonType(value))}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			found = found || allowed == value
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range asStrings(schema["required"]) {
+			if _, ok := value[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required %s", path, name))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, field := range value {
+			if property, ok := properties[name].(map[string]any); ok {
+				problems = append(problems, validateSchema(root, property, field, path+"."+name)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				problems = append(problems, validateSchema(root, additional, field, path+"."+name)...)
+			} else if schema["additionalProperties"] == false {
+				problems = append(problems, fmt.Sprintf("%s: unexpected property %s", path, name))
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				problems = append(problems, validateSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func asStrings(value any) []string {
+	var strs []string
+	list, _ := value.([]any)
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,88 @@
+package reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// formatJUnit formats results as JUnit XML: one test suite per module file,
+// one failing test case per finding and a passing test case for each module
+// without findings
+func (f *Formatter) formatJUnit(results []*analysis.ModuleAnalysisResult) (string, error) {
+	report := junitTestSuites{Name: ToolName}
+	files, byFile := resultsByFile(results)
+	for _, file := range files {
+		path := filepath.ToSlash(file)
+		suite := junitTestSuite{Name: path}
+		for _, result := range byFile[file] {
+			findings := result.AllFindings()
+			if len(findings) == 0 {
+				testCase := junitTestCase{Name: result.ModuleName, ClassName: result.ModuleName, File: path}
+				if result.Location != nil {
+					testCase.Line = result.Location.StartLine
+				}
+				suite.Cases = append(suite.Cases, testCase)
+				continue
+			}
+			for _, finding := range findings {
+				testCase := junitTestCase{
+					Name:      fmt.Sprintf("%s: %s", finding.RuleID, finding.Name),
+					ClassName: result.ModuleName,
+					File:      path,
+					Failure: &junitFailure{
+						Message: finding.Message,
+						Type:    finding.RuleID,
+						Text:    fmt.Sprintf("%s\n%s in %s", finding.Message, FormatPath(path, finding.Location), result.ModuleName),
+					},
+				}
+				if finding.Location != nil {
+					testCase.Line = finding.Location.StartLine
+				}
+				suite.Cases = append(suite.Cases, testCase)
+				suite.Failures++
+			}
+		}
+		suite.Tests = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,62 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+// xmlReportResults are results out of order, with a clean module
+func xmlReportResults() []*analysis.ModuleAnalysisResult {
+	return []*analysis.ModuleAnalysisResult{
+		{
+			ModuleName: "UsersModule",
+			FilePath:   "src/users/users.module.ts",
+			Location:   &analysis.Location{StartLine: 9, StartColumn: 14},
+		},
+		{
+			ModuleName:      "AppModule",
+			FilePath:        "src/app.module.ts",
+			UnusedImports:   []string{"UnusedModule"},
+			Location:        &analysis.Location{StartLine: 8, StartColumn: 14},
+			ImportLocations: map[string]analysis.Location{"UnusedModule": {StartLine: 5, StartColumn: 13}},
+			Findings: []analysis.Finding{{
+				RuleID:   analysis.RuleMissingImport,
+				Name:     "UsersService",
+				Message:  "UsersService is injected but <UsersModule> is not imported",
+				Location: &analysis.Location{StartLine: 8, StartColumn: 14},
+			}},
+		},
+	}
+}
+
+func TestFormatter_Format_JUnit(t *testing.T) {
+	output, err := reporting.NewFormatter().Format(xmlReportResults(), reporting.FormatJUnit)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="nestjs-module-lint" tests="3" failures="2">
+  <testsuite name="src/app.module.ts" tests="2" failures="2">
+    <testcase name="unused-import: UnusedModule" classname="AppModule" file="src/app.module.ts" line="5">
+      <failure message="UnusedModule is imported but no provider or controller of AppModule uses anything it exports" type="unused-import">UnusedModule is imported but no provider or controller of AppModule uses anything it exports&#xA;src/app.module.ts:5:13 in AppModule</failure>
+    </testcase>
+    <testcase name="missing-import: UsersService" classname="AppModule" file="src/app.module.ts" line="8">
+      <failure message="UsersService is injected but &lt;UsersModule&gt; is not imported" type="missing-import">UsersService is injected but &lt;UsersModule&gt; is not imported&#xA;src/app.module.ts:8:14 in AppModule</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="src/users/users.module.ts" tests="1" failures="0">
+    <testcase name="UsersModule" classname="UsersModule" file="src/users/users.module.ts" line="9"></testcase>
+  </testsuite>
+</testsuites>`
+	if output != expected {
+		t.Errorf("Output mismatch\nGot:\n%s\n\nExpected:\n%s", output, expected)
+	}
+
+	again, err := reporting.NewFormatter().Format(xmlReportResults(), reporting.FormatJUnit)
+	if err != nil || again != output {
+		t.Errorf("Expected the same output on every run")
+	}
+}
@@ -0,0 +1,201 @@
+package reporting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+)
+
+// DefaultMarkdownMaxSize keeps markdown reports under the 65536 character
+// limit of GitHub comments, with room left for text around them
+const DefaultMarkdownMaxSize = 60000
+
+// MinMarkdownMaxSize is the smallest size limit of markdown reports that
+// fits the heading, the table of counts and a truncation note
+const MinMarkdownMaxSize = 500
+
+// markdownFrameContext is the number of lines shown around a finding
+const markdownFrameContext = 2
+
+// WithMarkdownMaxSize limits markdown reports to a size in bytes. Module
+// sections that do not fit are shortened or left out, and a note tells how
+// many were, and the table of rules is left out when it does not fit. A size
+// of zero or less removes the limit; sizes under MinMarkdownMaxSize may be
+// exceeded by the heading and the table of counts.
+func (f *Formatter) WithMarkdownMaxSize(size int) *Formatter {
+	f.markdownMaxSize = size
+	return f
+}
+
+// formatMarkdown formats results as a markdown summary for pull request
+// comments: a table of counts, followed by a collapsible section per module
+// with findings, showing the code of each finding and the diff of its fix
+func (f *Formatter) formatMarkdown(results []*analysis.ModuleAnalysisResult) string {
+	files, byFile := resultsByFile(withFindings(results))
+	var sorted []*analysis.ModuleAnalysisResult
+	for _, file := range files {
+		sorted = append(sorted, byFile[file]...)
+	}
+	sources := make(map[string][]byte)
+
+	var report strings.Builder
+	report.WriteString(f.markdownSummary(results))
+
+	for i, result := range sorted {
+		section := f.markdownSection(result, sources, true)
+		if !f.markdownFits(report.Len() + len(section)) {
+			// Fall back to the findings without code
+			section = f.markdownSection(result, sources, false)
+		}
+		if !f.markdownFits(report.Len() + len(section)) {
+			report.WriteString(markdownTruncation(sorted[i:]))
+			break
+		}
+		report.WriteString(section)
+	}
+	return report.String()
+}
+
+// markdownFits reports whether a report of a size, followed by a truncation
+// note, stays within the size limit
+func (f *Formatter) markdownFits(size int) bool {
+	const truncationReserve = 200
+	return f.markdownMaxSize <= 0 || size+truncationReserve <= f.markdownMaxSize
+}
+
+// markdownSummary formats the heading and the tables of counts, leaving out
+// the table of rules when it does not fit the size limit
+func (f *Formatter) markdownSummary(results []*analysis.ModuleAnalysisResult) string {
+	counts := make(map[string]int)
+	total, failing := 0, 0
+	for _, result := range results {
+		findings := result.AllFindings()
+		if len(findings) > 0 {
+			failing++
+		}
+		for _, finding := range findings {
+			counts[finding.RuleID]++
+			total++
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("## %s\n\n", ToolName))
+	builder.WriteString("| Modules scanned | Modules with findings | Findings |\n")
+	builder.WriteString("| ---: | ---: | ---: |\n")
+	builder.WriteString(fmt.Sprintf("| %d | %d | %d |\n\n", len(results), failing, total))
+
+	if total == 0 {
+		builder.WriteString("No findings. :white_check_mark:\n")
+		return builder.String()
+	}
+
+	var rules strings.Builder
+	rules.WriteString("| Rule | Severity | Findings |\n")
+	rules.WriteString("| --- | --- | ---: |\n")
+	for _, rule := range analysis.Rules {
+		if counts[rule.ID] == 0 {
+			continue
+		}
+		rules.WriteString(fmt.Sprintf("| %s (`%s`) | %s | %d |\n", rule.Title, rule.ID, rule.Severity, counts[rule.ID]))
+	}
+	rules.WriteString("\n")
+	if f.markdownFits(builder.Len() + rules.Len()) {
+		builder.WriteString(rules.String())
+	}
+	return builder.String()
+}
+
+// markdownSection formats the collapsible section of a module, with or
+// without the code of its findings
+func (f *Formatter) markdownSection(result *analysis.ModuleAnalysisResult, sources map[string][]byte, withCode bool) string {
+	findings := result.AllFindings()
+	path := filepath.ToSlash(result.FilePath)
+
+	var builder strings.Builder
+	builder.WriteString("<details>\n")
+	builder.WriteString(fmt.Sprintf("<summary><strong>%s</strong> in <code>%s</code>: %s</summary>\n\n",
+		escapeHTML(result.ModuleName), escapeHTML(FormatPath(path, result.Location)), Pluralize(len(findings), "finding")))
+
+	source, readable := moduleSource(result.FilePath, sources)
+	for _, finding := range findings {
+		rule, _ := analysis.RuleByID(finding.RuleID)
+		builder.WriteString(fmt.Sprintf("- **%s** (`%s`, %s) `%s`: %s\n",
+			rule.Title, rule.ID, rule.Severity, FormatPath(path, finding.Location), escapeMarkdown(finding.Message)))
+		if !withCode || !readable {
+			continue
+		}
+
+		if finding.Location != nil {
+			if frame := fixing.CodeFrame(source, finding.Location.StartLine-1, markdownFrameContext); frame != "" {
+				builder.WriteString("\n")
+				builder.WriteString(markdownCodeBlock("ts", frame))
+			}
+		}
+		if edits, ok := f.fixes[fixKey(result.FilePath, result.ModuleName, finding.RuleID, finding.Name)]; ok {
+			fixed, err := fixing.ApplyEdits(source, edits)
+			if err != nil {
+				continue
+			}
+			if diff := fixing.UnifiedDiff(path, source, fixed); diff != "" {
+				builder.WriteString("\n  Suggested fix:\n\n")
+				builder.WriteString(markdownCodeBlock("diff", diff))
+			}
+		}
+	}
+	builder.WriteString("\n</details>\n\n")
+	return builder.String()
+}
+
+// moduleSource reads the source of a module file once per report. Code is
+// left out for files that cannot be read.
+func moduleSource(path string, sources map[string][]byte) ([]byte, bool) {
+	source, ok := sources[path]
+	if !ok {
+		source, _ = os.ReadFile(path)
+		sources[path] = source
+	}
+	return source, source != nil
+}
+
+// markdownTruncation notes how many modules and findings a report left out
+func markdownTruncation(omitted []*analysis.ModuleAnalysisResult) string {
+	findings := 0
+	for _, result := range omitted {
+		findings += len(result.AllFindings())
+	}
+	return fmt.Sprintf("_Report truncated: %s with %s not shown. Run `%s import-lint` locally for the full report._\n",
+		Pluralize(len(omitted), "more module"), Pluralize(findings, "finding"), ToolName)
+}
+
+// markdownCodeBlock fences content as an indented list item code block,
+// with a fence longer than any backtick run in the content
+func markdownCodeBlock(language, content string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	var builder strings.Builder
+	builder.WriteString("  " + fence + language + "\n")
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(content, "\n"), "\n") {
+		builder.WriteString("  " + line)
+	}
+	builder.WriteString("\n  " + fence + "\n")
+	return builder.String()
+}
+
+// escapeMarkdown escapes the characters of text that markdown would format
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "<", "&lt;", ">", "&gt;", "|", `\|`,
+	).Replace(text)
+}
+
+// escapeHTML escapes text for HTML elements such as the section summaries
+func escapeHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
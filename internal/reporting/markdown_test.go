@@ -0,0 +1,140 @@
+package reporting_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func TestFormatter_Format_Markdown(t *testing.T) {
+	source := `import { Module } from '@nestjs/common';
+import { UnusedModule } from './unused.module';
+
+@Module({
+  imports: [UnusedModule],
+})
+export class AppModule {}
+`
+	path := filepath.Join(t.TempDir(), "app.module.ts")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	results := []*analysis.ModuleAnalysisResult{
+		{ModuleName: "UsersModule", FilePath: "src/users/users.module.ts"},
+		{
+			ModuleName:      "AppModule",
+			FilePath:        path,
+			UnusedImports:   []string{"UnusedModule"},
+			ImportLocations: map[string]analysis.Location{"UnusedModule": {StartLine: 5, StartColumn: 13}},
+			Findings: []analysis.Finding{{
+				RuleID:  analysis.RuleMissingImport,
+				Name:    "UsersService",
+				Message: "UsersService is injected but <UsersModule> is not imported",
+			}},
+		},
+	}
+	unusedStatement := strings.Index(source, "import { UnusedModule }")
+	unusedElement := strings.Index(source, "UnusedModule]")
+	fixes := []fixing.FindingFix{{
+		File:   path,
+		Module: "AppModule",
+		RuleID: analysis.RuleUnusedImport,
+		Name:   "UnusedModule",
+		Edits: []fixing.Edit{
+			{Start: unusedStatement, End: unusedStatement + len("import { UnusedModule } from './unused.module';\n")},
+			{Start: unusedElement, End: unusedElement + len("UnusedModule")},
+		},
+	}}
+
+	output, err := reporting.NewFormatter().WithFixes(fixes).Format(results, reporting.FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		"| 2 | 1 | 2 |",
+		"| Unused Imports (`unused-import`) | warning | 1 |",
+		"| Missing Imports (`missing-import`) | error | 1 |",
+		"<summary><strong>AppModule</strong> in <code>" + filepath.ToSlash(path) + "</code>: 2 findings</summary>",
+		"UsersService is injected but &lt;UsersModule&gt; is not imported",
+		"  ```ts\n    3 | \n    4 | @Module({\n  > 5 |   imports: [UnusedModule],\n",
+		"  ```diff\n",
+		"  -import { UnusedModule } from './unused.module';\n",
+		"  -  imports: [UnusedModule],\n  +  imports: [],\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "UsersModule</strong>") {
+		t.Errorf("Expected no section for a module without findings, got:\n%s", output)
+	}
+}
+
+func TestFormatter_Format_Markdown_Truncates(t *testing.T) {
+	var results []*analysis.ModuleAnalysisResult
+	for _, name := range []string{"AModule", "BModule", "CModule", "DModule"} {
+		results = append(results, &analysis.ModuleAnalysisResult{
+			ModuleName:    name,
+			FilePath:      "src/" + strings.ToLower(name) + ".ts",
+			UnusedImports: []string{"UnusedModule"},
+		})
+	}
+
+	full, err := reporting.NewFormatter().WithMarkdownMaxSize(0).Format(results, reporting.FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if strings.Contains(full, "truncated") || strings.Count(full, "<details>") != 4 {
+		t.Fatalf("Expected every module without a limit, got:\n%s", full)
+	}
+
+	limit := len(full) - 300
+	output, err := reporting.NewFormatter().WithMarkdownMaxSize(limit).Format(results, reporting.FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if len(output) > limit {
+		t.Errorf("Expected at most %d bytes, got %d", limit, len(output))
+	}
+	if sections := strings.Count(output, "<details>"); sections == 0 || sections == 4 {
+		t.Errorf("Expected some of the modules, got %d", sections)
+	}
+	if !strings.Contains(output, "more module") || !strings.HasSuffix(output, "for the full report._\n") {
+		t.Errorf("Expected a truncation note, got:\n%s", output)
+	}
+	if strings.Count(output, "<details>") != strings.Count(output, "</details>") {
+		t.Errorf("Expected only whole sections, got:\n%s", output)
+	}
+}
+
+func TestFormatter_Format_Markdown_StaysWithinSmallLimits(t *testing.T) {
+	var findings []analysis.Finding
+	for _, rule := range analysis.Rules {
+		findings = append(findings, analysis.Finding{RuleID: rule.ID, Name: "UsersService", Message: "UsersService is flagged by " + rule.ID})
+	}
+	results := []*analysis.ModuleAnalysisResult{{
+		ModuleName:    "AppModule",
+		FilePath:      "src/app.module.ts",
+		UnusedImports: []string{"UnusedModule"},
+		Findings:      findings,
+	}}
+
+	for _, limit := range []int{reporting.MinMarkdownMaxSize, reporting.MinMarkdownMaxSize + 100, 1000} {
+		output, err := reporting.NewFormatter().WithMarkdownMaxSize(limit).Format(results, reporting.FormatMarkdown)
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+		if len(output) > limit {
+			t.Errorf("Expected at most %d bytes, got %d:\n%s", limit, len(output), output)
+		}
+		if !strings.Contains(output, "| Modules scanned |") || !strings.Contains(output, "Report truncated") {
+			t.Errorf("Expected the table of counts and a truncation note within %d bytes, got:\n%s", limit, output)
+		}
+	}
+}
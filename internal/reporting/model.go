@@ -0,0 +1,189 @@
+package reporting
+
+import (
+	"path/filepath"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+)
+
+// ReportModelVersion is the version of the Report data model. The minor
+// version grows when fields are added; the major version changes when
+// fields are renamed or removed, so templates can rely on it.
+const ReportModelVersion = "1.1"
+
+// Report is the data model of a lint run that templates render. Files and
+// modules are sorted, so the same code gives the same report.
+type Report struct {
+	// Version is ReportModelVersion
+	Version string     `json:"version"`
+	Tool    ReportTool `json:"tool"`
+	// Run is the run given to WithRun
+	Run ReportRun `json:"run"`
+	// Rules lists every rule, whether or not it reported findings
+	Rules []ReportRule `json:"rules"`
+	// Modules lists every analyzed module, including those without findings
+	Modules []ReportModule `json:"modules"`
+	// Findings lists the findings of all modules
+	Findings []ReportFinding `json:"findings"`
+	Stats    ReportStats     `json:"stats"`
+}
+
+// ReportTool identifies the linter
+type ReportTool struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	InformationURI string `json:"information_uri"`
+}
+
+// ReportRun describes the lint run that produced a report
+type ReportRun struct {
+	// Paths are the analyzed files and directories as given
+	Paths            []string `json:"paths"`
+	WorkingDirectory string   `json:"working_directory"`
+	// StartedAt is when the analysis started, in RFC 3339 format
+	StartedAt string `json:"started_at,omitempty"`
+	// DurationMS is how long the analysis took in milliseconds
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// ReportRule describes a rule
+type ReportRule struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Severity is "error" or "warning"
+	Severity string `json:"severity"`
+}
+
+// ReportModule is an analyzed module
+type ReportModule struct {
+	Name string `json:"name"`
+	// File is the module file, relative to the working directory with
+	// forward slashes
+	File string `json:"file"`
+	// Location is the module class name, if known
+	Location          *analysis.Location `json:"location,omitempty"`
+	UnusedImports     []string           `json:"unused_imports"`
+	IgnoredImports    []string           `json:"ignored_imports"`
+	ReExportedImports []string           `json:"reexported_imports"`
+	Findings          []ReportFinding    `json:"findings"`
+}
+
+// ReportFinding is a rule violation within a module
+type ReportFinding struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	// Name is the element the finding is about, e.g. the unused import
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Module  string `json:"module"`
+	File    string `json:"file"`
+	// Location is the element, or the module class name when the finding
+	// has no element
+	Location *analysis.Location `json:"location,omitempty"`
+	// Fingerprint identifies the finding across runs, independent of its
+	// line
+	Fingerprint string `json:"fingerprint"`
+	// Fix lists the byte replacements that fix the finding alone, in the
+	// original file, or nothing when it has no automatic fix
+	Fix []fixing.Edit `json:"fix,omitempty"`
+}
+
+// ReportStats counts the modules and findings of a run
+type ReportStats struct {
+	Modules             int `json:"modules"`
+	ModulesWithFindings int `json:"modules_with_findings"`
+	Findings            int `json:"findings"`
+	Errors              int `json:"errors"`
+	Warnings            int `json:"warnings"`
+	// FindingsByRule counts the findings of each rule ID, including rules
+	// without findings
+	FindingsByRule map[string]int `json:"findings_by_rule"`
+}
+
+// WithRun describes the run that produced the results in reports
+func (f *Formatter) WithRun(run ReportRun) *Formatter {
+	f.run = run
+	return f
+}
+
+// BuildReport builds the report data model of results, including the fixes
+// given to WithFixes
+func (f *Formatter) BuildReport(results []*analysis.ModuleAnalysisResult) *Report {
+	report := &Report{
+		Version: ReportModelVersion,
+		Tool: ReportTool{
+			Name:           ToolName,
+			Version:        ToolVersion,
+			InformationURI: ToolInformationURI,
+		},
+		Run:      f.run,
+		Rules:    []ReportRule{},
+		Modules:  []ReportModule{},
+		Findings: []ReportFinding{},
+		Stats:    ReportStats{FindingsByRule: make(map[string]int)},
+	}
+	for _, rule := range analysis.Rules {
+		report.Rules = append(report.Rules, ReportRule{
+			ID:          rule.ID,
+			Title:       rule.Title,
+			Description: rule.Description,
+			Severity:    rule.Severity,
+		})
+		report.Stats.FindingsByRule[rule.ID] = 0
+	}
+
+	fingerprints := newFingerprinter()
+	files, byFile := resultsByFile(results)
+	for _, file := range files {
+		for _, result := range byFile[file] {
+			module := ReportModule{
+				Name:              result.ModuleName,
+				File:              filepath.ToSlash(result.FilePath),
+				Location:          result.Location,
+				UnusedImports:     nonNil(result.UnusedImports),
+				IgnoredImports:    nonNil(result.IgnoredImports),
+				ReExportedImports: nonNil(result.ReExportedImports),
+				Findings:          []ReportFinding{},
+			}
+			for _, finding := range result.AllFindings() {
+				rule, _ := analysis.RuleByID(finding.RuleID)
+				module.Findings = append(module.Findings, ReportFinding{
+					RuleID:      finding.RuleID,
+					Severity:    rule.Severity,
+					Name:        finding.Name,
+					Message:     finding.Message,
+					Module:      result.ModuleName,
+					File:        module.File,
+					Location:    finding.Location,
+					Fingerprint: fingerprints.fingerprint(result, finding),
+					Fix:         f.fixes[fixKey(result.FilePath, result.ModuleName, finding.RuleID, finding.Name)],
+				})
+				report.Stats.FindingsByRule[finding.RuleID]++
+				if rule.Severity == analysis.SeverityError {
+					report.Stats.Errors++
+				} else {
+					report.Stats.Warnings++
+				}
+			}
+			report.Modules = append(report.Modules, module)
+			report.Findings = append(report.Findings, module.Findings...)
+			report.Stats.Modules++
+			if len(module.Findings) > 0 {
+				report.Stats.ModulesWithFindings++
+			}
+		}
+	}
+	report.Stats.Findings = len(report.Findings)
+	report.Run.Paths = nonNil(report.Run.Paths)
+	return report
+}
+
+// nonNil returns names, or an empty list for nil so that it is encoded as []
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
@@ -0,0 +1,248 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSourceRoot is the base of artifact URIs, which are relative to
+	// the working directory
+	sarifSourceRoot = "%SRCROOT%"
+	// sarifFingerprint names the fingerprint of results, versioned in case
+	// its computation changes
+	sarifFingerprint = "nestjsModuleLint/v1"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+	// ColumnKind tells how columns are counted. Regions count code points,
+	// converted from the byte columns of locations.
+	ColumnKind string `json:"columnKind"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	// PartialFingerprints identify the result across runs
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Fixes               []sarifFix        `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifByteRegion `json:"deletedRegion"`
+	InsertedContent *sarifContent   `json:"insertedContent,omitempty"`
+}
+
+type sarifByteRegion struct {
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+}
+
+type sarifContent struct {
+	Text string `json:"text"`
+}
+
+// formatSARIF formats results as a SARIF 2.1.0 log with one result per
+// finding, including the fixes given to WithFixes
+func (f *Formatter) formatSARIF(results []*analysis.ModuleAnalysisResult) (string, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           ToolName,
+			Version:        ToolVersion,
+			InformationURI: ToolInformationURI,
+		}},
+		Results:    make([]sarifResult, 0),
+		ColumnKind: "unicodeCodePoints",
+	}
+	ruleIndexes := make(map[string]int)
+	for i, rule := range analysis.Rules {
+		ruleIndexes[rule.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			Name:                 ruleName(rule.ID),
+			ShortDescription:     sarifMessage{Text: rule.Title},
+			FullDescription:      sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Severity},
+		})
+	}
+
+	fingerprints := newFingerprinter()
+	sources := make(map[string][]byte)
+	for _, result := range results {
+		source, _ := moduleSource(result.FilePath, sources)
+		artifact := sarifArtifactLocation{URI: filepath.ToSlash(result.FilePath), URIBaseID: sarifSourceRoot}
+		for _, finding := range result.AllFindings() {
+			rule, _ := analysis.RuleByID(finding.RuleID)
+			sarifResult := sarifResult{
+				RuleID:    finding.RuleID,
+				RuleIndex: ruleIndexes[finding.RuleID],
+				Level:     rule.Severity,
+				Message:   sarifMessage{Text: finding.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: sarifLocationRegion(finding.Location, source)},
+					LogicalLocations: []sarifLogicalLocation{{Name: result.ModuleName, Kind: "module"}},
+				}},
+				PartialFingerprints: map[string]string{sarifFingerprint: fingerprints.fingerprint(result, finding)},
+			}
+			if edits, ok := f.fixes[fixKey(result.FilePath, result.ModuleName, finding.RuleID, finding.Name)]; ok {
+				sarifResult.Fixes = []sarifFix{sarifEditsFix(artifact, edits)}
+			}
+			run.Results = append(run.Results, sarifResult)
+		}
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	return string(data), nil
+}
+
+// sarifLocationRegion converts a location to a SARIF region, counting its
+// columns in code points of the module source. The byte columns are kept
+// when the source cannot be read.
+func sarifLocationRegion(location *analysis.Location, source []byte) *sarifRegion {
+	if location == nil {
+		return nil
+	}
+	region := &sarifRegion{
+		StartLine:   location.StartLine,
+		StartColumn: location.StartColumn,
+		EndLine:     location.EndLine,
+		EndColumn:   location.EndColumn,
+	}
+	if column, ok := codePointColumn(source, location.StartByte); ok {
+		region.StartColumn = column
+	}
+	if column, ok := codePointColumn(source, location.EndByte); ok {
+		region.EndColumn = column
+	}
+	return region
+}
+
+// codePointColumn returns the column of a byte offset in source, starting
+// at 1 and counting code points. A byte order mark is not counted.
+func codePointColumn(source []byte, offset int) (int, bool) {
+	if source == nil || offset < 0 || offset > len(source) {
+		return 0, false
+	}
+	lineStart := bytes.LastIndexByte(source[:offset], '\n') + 1
+	prefix := bytes.TrimPrefix(source[lineStart:offset], []byte("\xEF\xBB\xBF"))
+	return utf8.RuneCount(prefix) + 1, true
+}
+
+// sarifEditsFix converts the edits fixing a finding to a SARIF fix
+func sarifEditsFix(artifact sarifArtifactLocation, edits []fixing.Edit) sarifFix {
+	change := sarifArtifactChange{ArtifactLocation: artifact}
+	var reasons []string
+	for _, edit := range edits {
+		replacement := sarifReplacement{DeletedRegion: sarifByteRegion{ByteOffset: edit.Start, ByteLength: edit.End - edit.Start}}
+		if edit.Replacement != "" {
+			replacement.InsertedContent = &sarifContent{Text: edit.Replacement}
+		}
+		change.Replacements = append(change.Replacements, replacement)
+		if edit.Reason != "" {
+			reasons = append(reasons, edit.Reason)
+		}
+	}
+	return sarifFix{
+		Description:     sarifMessage{Text: strings.Join(reasons, "; ")},
+		ArtifactChanges: []sarifArtifactChange{change},
+	}
+}
+
+// ruleName converts a rule ID to the PascalCase name SARIF expects, e.g.
+// unused-import to UnusedImport
+func ruleName(id string) string {
+	var name strings.Builder
+	for _, word := range strings.Split(id, "-") {
+		if word != "" {
+			name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return name.String()
+}
@@ -0,0 +1,187 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func TestFormatter_Format_SARIF(t *testing.T) {
+	results := []*analysis.ModuleAnalysisResult{
+		{
+			ModuleName:    "AppModule",
+			FilePath:      "src/app.module.ts",
+			UnusedImports: []string{"UnusedModule"},
+			Location:      &analysis.Location{StartLine: 8, StartColumn: 14, EndLine: 8, EndColumn: 23},
+			ImportLocations: map[string]analysis.Location{
+				"UnusedModule": {StartLine: 5, StartColumn: 13, EndLine: 5, EndColumn: 25, StartByte: 112, EndByte: 124},
+			},
+			Findings: []analysis.Finding{{
+				RuleID:  analysis.RuleMissingImport,
+				Name:    "UsersService",
+				Message: "UsersService is injected but its module is not imported",
+			}},
+		},
+	}
+	fixes := []fixing.FindingFix{{
+		File:   "src/app.module.ts",
+		Module: "AppModule",
+		RuleID: analysis.RuleUnusedImport,
+		Name:   "UnusedModule",
+		Edits: []fixing.Edit{
+			{Start: 41, End: 89, Reason: "remove unused import of UnusedModule"},
+			{Start: 112, End: 124, Replacement: "", Reason: "remove unused import UnusedModule from AppModule"},
+		},
+	}}
+
+	output, err := reporting.NewFormatter().WithFixes(fixes).Format(results, reporting.FormatSARIF)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string            `json:"ruleId"`
+				RuleIndex int               `json:"ruleIndex"`
+				Level     string            `json:"level"`
+				Partial   map[string]string `json:"partialFingerprints"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Fixes []struct {
+					ArtifactChanges []struct {
+						Replacements []struct {
+							DeletedRegion struct {
+								ByteOffset int `json:"byteOffset"`
+								ByteLength int `json:"byteLength"`
+							} `json:"deletedRegion"`
+						} `json:"replacements"`
+					} `json:"artifactChanges"`
+				} `json:"fixes"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Expected a SARIF 2.1.0 log with one run, got:\n%s", output)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != reporting.ToolName || len(run.Tool.Driver.Rules) != len(analysis.Rules) {
+		t.Errorf("Expected the driver to describe every rule, got %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+
+	unused := run.Results[0]
+	if unused.RuleID != analysis.RuleUnusedImport || unused.Level != analysis.SeverityWarning {
+		t.Errorf("Unexpected unused import result %+v", unused)
+	}
+	if rule := run.Tool.Driver.Rules[unused.RuleIndex]; rule.ID != unused.RuleID {
+		t.Errorf("Expected rule index %d to point at %s, got %s", unused.RuleIndex, unused.RuleID, rule.ID)
+	}
+	if unused.Partial["nestjsModuleLint/v1"] == "" {
+		t.Errorf("Expected a partial fingerprint, got %v", unused.Partial)
+	}
+	location := unused.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "src/app.module.ts" || location.Region == nil || location.Region.StartLine != 5 || location.Region.StartColumn != 13 {
+		t.Errorf("Expected the unused import at src/app.module.ts:5:13, got %+v", location)
+	}
+	if len(unused.Fixes) != 1 || len(unused.Fixes[0].ArtifactChanges[0].Replacements) != 2 {
+		t.Fatalf("Expected a fix with 2 replacements, got %+v", unused.Fixes)
+	}
+	if deleted := unused.Fixes[0].ArtifactChanges[0].Replacements[1].DeletedRegion; deleted.ByteOffset != 112 || deleted.ByteLength != 12 {
+		t.Errorf("Expected the element to be deleted at 112+12, got %+v", deleted)
+	}
+
+	// A finding without a location has no region
+	missing := run.Results[1]
+	if missing.Level != analysis.SeverityError || len(missing.Fixes) != 0 {
+		t.Errorf("Unexpected missing import result %+v", missing)
+	}
+	if region := missing.Locations[0].PhysicalLocation.Region; region != nil {
+		t.Errorf("Expected no region without a finding location, got %+v", region)
+	}
+}
+
+func TestFormatter_Format_SARIF_CodePointColumns(t *testing.T) {
+	// The comment before UnusedModule has multi-byte characters, and the
+	// file starts with a byte order mark
+	source := "\xEF\xBB\xBF@Module({ imports: [/* dépendance inutilisée */ UnusedModule] })\nexport class AppModule {}\n"
+	path := filepath.Join(t.TempDir(), "app.module.ts")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	start := strings.Index(source, "UnusedModule")
+	end := start + len("UnusedModule")
+	results := []*analysis.ModuleAnalysisResult{{
+		ModuleName:    "AppModule",
+		FilePath:      path,
+		UnusedImports: []string{"UnusedModule"},
+		ImportLocations: map[string]analysis.Location{
+			"UnusedModule": {StartLine: 1, StartColumn: start + 1, EndLine: 1, EndColumn: end + 1, StartByte: start, EndByte: end},
+		},
+	}}
+
+	output, err := reporting.NewFormatter().Format(results, reporting.FormatSARIF)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	var log struct {
+		Runs []struct {
+			ColumnKind string `json:"columnKind"`
+			Results    []struct {
+				Locations []struct {
+					PhysicalLocation struct {
+						Region struct {
+							StartColumn int `json:"startColumn"`
+							EndColumn   int `json:"endColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	run := log.Runs[0]
+	region := run.Results[0].Locations[0].PhysicalLocation.Region
+	expectedStart := utf8.RuneCountInString("@Module({ imports: [/* dépendance inutilisée */ ") + 1
+	if run.ColumnKind != "unicodeCodePoints" || region.StartColumn != expectedStart || region.EndColumn != expectedStart+len("UnusedModule") {
+		t.Errorf("Expected code point columns %d-%d, got %s %d-%d",
+			expectedStart, expectedStart+len("UnusedModule"), run.ColumnKind, region.StartColumn, region.EndColumn)
+	}
+}
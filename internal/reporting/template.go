@@ -0,0 +1,101 @@
+package reporting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// ErrNoTemplate is returned when formatting with FormatTemplate without a
+// template
+var ErrNoTemplate = errors.New("no template given")
+
+// TemplateFuncs returns the helper functions available to templates:
+//
+//	relpath BASE PATH  PATH relative to BASE, with forward slashes
+//	abspath PATH       PATH as an absolute path
+//	join SEP LIST      the elements of LIST separated by SEP
+//	json VALUE         VALUE encoded as JSON
+//	csv FIELD...       the fields as a CSV record, without line break
+//	location FILE LOC  FILE followed by the line and column LOC starts at
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"relpath": func(base, path string) (string, error) {
+			relative, err := filepath.Rel(filepath.FromSlash(base), filepath.FromSlash(path))
+			if err != nil {
+				return "", err
+			}
+			return filepath.ToSlash(relative), nil
+		},
+		"abspath": func(path string) (string, error) {
+			return filepath.Abs(filepath.FromSlash(path))
+		},
+		"join": func(separator string, items []string) string {
+			return strings.Join(items, separator)
+		},
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+		"csv":      csvRecord,
+		"location": FormatPath,
+	}
+}
+
+// ParseTemplate parses a report template file with the helper functions of
+// TemplateFuncs
+func ParseTemplate(path string) (*template.Template, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).
+		Funcs(TemplateFuncs()).
+		Option("missingkey=error").
+		Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// WithTemplate sets the template FormatTemplate renders
+func (f *Formatter) WithTemplate(tmpl *template.Template) *Formatter {
+	f.template = tmpl
+	return f
+}
+
+// formatTemplate renders the report data model of results with the template
+func (f *Formatter) formatTemplate(results []*analysis.ModuleAnalysisResult) (string, error) {
+	if f.template == nil {
+		return "", ErrNoTemplate
+	}
+	var builder strings.Builder
+	if err := f.template.Execute(&builder, f.BuildReport(results)); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return builder.String(), nil
+}
+
+// csvRecord formats fields as a CSV record, quoting the fields that contain
+// separators, quotes or line breaks
+func csvRecord(fields ...any) string {
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		value := fmt.Sprint(field)
+		if strings.ContainsAny(value, ",\"\r\n") {
+			value = `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+		}
+		quoted[i] = value
+	}
+	return strings.Join(quoted, ",")
+}
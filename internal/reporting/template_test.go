@@ -0,0 +1,108 @@
+package reporting_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/fixing"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+func parseTemplate(t *testing.T, source string) *reporting.Formatter {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	tmpl, err := reporting.ParseTemplate(path)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	return reporting.NewFormatter().WithTemplate(tmpl)
+}
+
+func TestFormatter_Format_Template(t *testing.T) {
+	fixes := []fixing.FindingFix{{
+		File:   "src/app.module.ts",
+		Module: "AppModule",
+		RuleID: analysis.RuleUnusedImport,
+		Name:   "UnusedModule",
+		Edits:  []fixing.Edit{{Start: 112, End: 124}},
+	}}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "Stats",
+			template: `{{.Version}} {{.Tool.Name}}: {{.Stats.Findings}} findings ({{.Stats.Errors}} errors, {{.Stats.Warnings}} warnings) in {{.Stats.ModulesWithFindings}}/{{.Stats.Modules}} modules, {{index .Stats.FindingsByRule "unused-queue"}} unused queues`,
+			expected: "1.1 nestjs-module-lint: 2 findings (1 errors, 1 warnings) in 1/2 modules, 0 unused queues",
+		},
+		{
+			name:     "Modules",
+			template: `{{range .Modules}}{{.Name}} {{.File}} [{{join ", " .UnusedImports}}]{{"\n"}}{{end}}`,
+			expected: "AppModule src/app.module.ts [UnusedModule]\nUsersModule src/users/users.module.ts []\n",
+		},
+		{
+			name:     "CSV",
+			template: `{{range .Findings}}{{csv (location .File .Location) .RuleID .Severity .Message}}{{"\n"}}{{end}}`,
+			expected: "src/app.module.ts:5:13,unused-import,warning,UnusedModule is imported but no provider or controller of AppModule uses anything it exports\n" +
+				"src/app.module.ts:8:14,missing-import,error,UsersService is injected but <UsersModule> is not imported\n",
+		},
+		{
+			name:     "Fixes",
+			template: `{{range .Findings}}{{.Name}}: {{json .Fix}}{{"\n"}}{{end}}`,
+			expected: "UnusedModule: [{\"start\":112,\"end\":124,\"replacement\":\"\",\"reason\":\"\"}]\nUsersService: null\n",
+		},
+		{
+			name:     "Rules",
+			template: `{{range .Rules}}{{if eq .Severity "error"}}{{.ID}} {{end}}{{end}}`,
+			expected: "unregistered-queue missing-import ",
+		},
+		{
+			name:     "Paths",
+			template: `{{relpath "src" "src/users/users.module.ts"}} {{relpath "src/users" "src/app.module.ts"}}`,
+			expected: "users/users.module.ts ../app.module.ts",
+		},
+		{
+			name:     "CSV quoting",
+			template: `{{csv "a,b" "say \"hi\"" 3}}`,
+			expected: `"a,b","say ""hi""",3`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := parseTemplate(t, tt.template).WithFixes(fixes).Format(xmlReportResults(), reporting.FormatTemplate)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Output mismatch\nGot:\n%s\n\nExpected:\n%s", output, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatter_Format_Template_Errors(t *testing.T) {
+	if _, err := reporting.NewFormatter().Format(xmlReportResults(), reporting.FormatTemplate); !errors.Is(err, reporting.ErrNoTemplate) {
+		t.Errorf("Expected ErrNoTemplate without a template, got %v", err)
+	}
+
+	if _, err := parseTemplate(t, "{{.Unknown}}").Format(xmlReportResults(), reporting.FormatTemplate); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	if err := os.WriteFile(path, []byte("{{range .Modules}}"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if _, err := reporting.ParseTemplate(path); err == nil {
+		t.Errorf("Expected a parse error for an unclosed range")
+	}
+}
@@ -0,0 +1,224 @@
+package reporting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+)
+
+// verboseFrameContext is the number of lines shown around each import and
+// finding of verbose text output
+const verboseFrameContext = 1
+
+// ANSI escape sequences of colored verbose text output
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// Verdicts of the imports of a module in verbose text output
+const (
+	verdictUsed       = "used"
+	verdictUnused     = "unused"
+	verdictIgnored    = "ignored"
+	verdictReExported = "re-exported"
+)
+
+// verdictStyles colors each verdict
+var verdictStyles = map[string]string{
+	verdictUsed:       ansiGreen,
+	verdictUnused:     ansiRed,
+	verdictIgnored:    ansiYellow,
+	verdictReExported: ansiCyan,
+}
+
+// WithVerbose makes text output explain every module: the verdict of each
+// import and why it was reached, and the code of each import and finding
+func (f *Formatter) WithVerbose(verbose bool) *Formatter {
+	f.verbose = verbose
+	return f
+}
+
+// WithColor highlights verbose text output with ANSI colors, for terminals
+func (f *Formatter) WithColor(color bool) *Formatter {
+	f.color = color
+	return f
+}
+
+// importVerdict is the verdict on one import of a module and its reason
+type importVerdict struct {
+	name     string
+	verdict  string
+	reason   string
+	location *analysis.Location
+}
+
+// formatVerboseText formats every module as human-readable text, listing
+// each import with its verdict and showing the code of imports and findings
+func (f *Formatter) formatVerboseText(results []*analysis.ModuleAnalysisResult) string {
+	if len(results) == 0 {
+		return "No modules found."
+	}
+
+	files, byFile := resultsByFile(results)
+	sources := make(map[string][]byte)
+	var builder strings.Builder
+	for i, file := range files {
+		for j, result := range byFile[file] {
+			if i > 0 || j > 0 {
+				builder.WriteString("\n")
+			}
+			source, _ := moduleSource(result.FilePath, sources)
+			builder.WriteString(f.formatVerboseModule(result, source))
+		}
+	}
+
+	builder.WriteString("\n" + f.GetSummary(results, false) + "\n")
+	return builder.String()
+}
+
+// formatVerboseModule formats the imports and findings of a module, with
+// code frames from its source when it could be read
+func (f *Formatter) formatVerboseModule(result *analysis.ModuleAnalysisResult, source []byte) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Module: %s\n", f.paint(ansiBold, result.ModuleName)))
+	builder.WriteString(fmt.Sprintf("Path: %s\n", FormatPath(result.FilePath, result.Location)))
+
+	if verdicts := importVerdicts(result); len(verdicts) > 0 {
+		builder.WriteString("Imports:\n")
+		for _, verdict := range verdicts {
+			style := verdictStyles[verdict.verdict]
+			builder.WriteString(fmt.Sprintf("\t%s: %s - %s%s\n",
+				verdict.name, f.paint(style, verdict.verdict), verdict.reason, f.paint(ansiDim, FormatLocation(verdict.location))))
+			builder.WriteString(f.codeFrame(source, verdict.location, style))
+		}
+	}
+
+	for _, rule := range analysis.Rules {
+		findings := result.FindingsByRule(rule.ID)
+		if len(findings) == 0 {
+			continue
+		}
+		style := ansiYellow
+		if rule.Severity == analysis.SeverityError {
+			style = ansiRed
+		}
+		builder.WriteString(fmt.Sprintf("%s:\n", rule.Title))
+		for _, finding := range findings {
+			builder.WriteString(fmt.Sprintf("\t%s - %s%s\n",
+				f.paint(style, finding.Name), finding.Message, f.paint(ansiDim, FormatLocation(finding.Location))))
+			builder.WriteString(f.codeFrame(source, finding.Location, style))
+		}
+	}
+	return builder.String()
+}
+
+// importVerdicts returns the verdict on each import of a module in the
+// order the imports are declared, when their locations are known
+func importVerdicts(result *analysis.ModuleAnalysisResult) []importVerdict {
+	var verdicts []importVerdict
+	add := func(name, verdict, reason string) {
+		var location *analysis.Location
+		if loc, ok := result.ImportLocations[name]; ok {
+			location = &loc
+		}
+		verdicts = append(verdicts, importVerdict{name: name, verdict: verdict, reason: reason, location: location})
+	}
+
+	for _, usage := range result.UsedImports {
+		add(usage.Name, verdictUsed, usageReason(result.ModuleName, usage))
+	}
+	for _, name := range result.UnusedImports {
+		add(name, verdictUnused, fmt.Sprintf("no provider or controller of %s uses anything it exports", result.ModuleName))
+	}
+	for _, name := range result.IgnoredImports {
+		add(name, verdictIgnored, "kept by an ignore comment")
+	}
+	for _, name := range result.ReExportedImports {
+		add(name, verdictReExported, fmt.Sprintf("%s exports it again", result.ModuleName))
+	}
+
+	sort.SliceStable(verdicts, func(i, j int) bool {
+		a, b := verdicts[i].location, verdicts[j].location
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.StartByte < b.StartByte
+	})
+	return verdicts
+}
+
+// usageReason explains why an import counts as used
+func usageReason(moduleName string, usage analysis.ImportUsage) string {
+	switch {
+	case usage.External:
+		return "imported from a package, which is not analyzed"
+	case usage.Symbol == "":
+		return "its usage could not be analyzed"
+	case usage.Provider == "":
+		return fmt.Sprintf("a provider definition of %s injects %s, which it exports", moduleName, usage.Symbol)
+	default:
+		return fmt.Sprintf("%s uses %s, which it exports", usage.Provider, usage.Symbol)
+	}
+}
+
+// codeFrame returns the lines around a location, numbered and indented, with
+// its first line marked like fixing.CodeFrame. With color, the location is
+// highlighted in style.
+func (f *Formatter) codeFrame(source []byte, location *analysis.Location, style string) string {
+	if location == nil || source == nil {
+		return ""
+	}
+	lines := strings.Split(string(source), "\n")
+	row := location.StartLine - 1
+	if row < 0 || row >= len(lines) {
+		return ""
+	}
+	first := max(row-verboseFrameContext, 0)
+	last := min(row+verboseFrameContext, len(lines)-1)
+	width := len(fmt.Sprint(last + 1))
+
+	var frame strings.Builder
+	for i := first; i <= last; i++ {
+		marker := " "
+		if i == row {
+			marker = f.paint(ansiBold+style, ">")
+		}
+		line := strings.TrimSuffix(lines[i], "\r")
+		if f.color && i+1 >= location.StartLine && i+1 <= location.EndLine {
+			line = highlightLine(line, i+1, location, style)
+		}
+		fmt.Fprintf(&frame, "\t\t%s %s | %s\n", marker, f.paint(ansiDim, fmt.Sprintf("%*d", width, i+1)), line)
+	}
+	return frame.String()
+}
+
+// highlightLine highlights the part of a numbered line within a location
+func highlightLine(line string, number int, location *analysis.Location, style string) string {
+	start, end := 0, len(line)
+	if number == location.StartLine {
+		start = min(max(location.StartColumn-1, 0), len(line))
+	}
+	if number == location.EndLine {
+		end = min(max(location.EndColumn-1, 0), len(line))
+	}
+	if start >= end {
+		return line
+	}
+	return line[:start] + ansiBold + style + line[start:end] + ansiReset + line[end:]
+}
+
+// paint wraps text in an ANSI style when color is enabled
+func (f *Formatter) paint(style, text string) string {
+	if !f.color || text == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
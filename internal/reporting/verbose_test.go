@@ -0,0 +1,152 @@
+package reporting_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/nestjs-module-lint/internal/analysis"
+	"github.com/evanrichards/nestjs-module-lint/internal/reporting"
+)
+
+const verboseSource = `import { Module } from '@nestjs/common';
+
+@Module({
+  imports: [UsersModule, OrdersModule, LegacyModule, SharedModule, ConfigModule],
+  exports: [SharedModule],
+})
+export class AppModule {}
+`
+
+// verboseResults returns the result of AppModule in a file with
+// verboseSource, locating each import in the source
+func verboseResults(t *testing.T) []*analysis.ModuleAnalysisResult {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.module.ts")
+	if err := os.WriteFile(path, []byte(verboseSource), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	locate := func(name string) analysis.Location {
+		start := strings.Index(verboseSource, name)
+		line := strings.Count(verboseSource[:start], "\n") + 1
+		column := start - strings.LastIndex(verboseSource[:start], "\n")
+		return analysis.Location{
+			StartLine: line, StartColumn: column, EndLine: line, EndColumn: column + len(name),
+			StartByte: start, EndByte: start + len(name),
+		}
+	}
+	location := locate("AppModule")
+	imports := []string{"UsersModule", "OrdersModule", "LegacyModule", "SharedModule"}
+	importLocations := make(map[string]analysis.Location)
+	for _, name := range imports {
+		importLocations[name] = locate(name)
+	}
+
+	return []*analysis.ModuleAnalysisResult{{
+		ModuleName:        "AppModule",
+		FilePath:          path,
+		UnusedImports:     []string{"OrdersModule"},
+		IgnoredImports:    []string{"LegacyModule"},
+		ReExportedImports: []string{"SharedModule"},
+		UsedImports: []analysis.ImportUsage{
+			{Name: "ConfigModule", External: true},
+			{Name: "UsersModule", Provider: "AppService", Symbol: "UsersService"},
+		},
+		Location:        &location,
+		ImportLocations: importLocations,
+	}}
+}
+
+func TestFormatter_Format_VerboseText(t *testing.T) {
+	results := verboseResults(t)
+	output, err := reporting.NewFormatter().WithVerbose(true).Format(results, reporting.FormatText)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	frame := "\t\t  3 | @Module({\n" +
+		"\t\t> 4 |   imports: [UsersModule, OrdersModule, LegacyModule, SharedModule, ConfigModule],\n" +
+		"\t\t  5 |   exports: [SharedModule],\n"
+	expected := "Module: AppModule\n" +
+		"Path: " + results[0].FilePath + ":7:14\n" +
+		"Imports:\n" +
+		"\tUsersModule: used - AppService uses UsersService, which it exports (line 4, column 13)\n" + frame +
+		"\tOrdersModule: unused - no provider or controller of AppModule uses anything it exports (line 4, column 26)\n" + frame +
+		"\tLegacyModule: ignored - kept by an ignore comment (line 4, column 40)\n" + frame +
+		"\tSharedModule: re-exported - AppModule exports it again (line 4, column 54)\n" + frame +
+		"\tConfigModule: used - imported from a package, which is not analyzed\n" +
+		"\nTotal number of modules with findings: 1 (unused-import: 1)\n"
+	if output != expected {
+		t.Errorf("Output mismatch\nGot:\n%s\n\nExpected:\n%s", output, expected)
+	}
+}
+
+func TestFormatter_Format_VerboseText_Color(t *testing.T) {
+	results := verboseResults(t)
+
+	plain, err := reporting.NewFormatter().WithVerbose(true).Format(results, reporting.FormatText)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("Expected no escape sequences without color, got:\n%q", plain)
+	}
+
+	colored, err := reporting.NewFormatter().WithVerbose(true).WithColor(true).Format(results, reporting.FormatText)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	for _, expected := range []string{
+		"\x1b[32mused\x1b[0m",
+		"\x1b[31munused\x1b[0m",
+		// The unused import is highlighted in its frame
+		"imports: [UsersModule, \x1b[1m\x1b[31mOrdersModule\x1b[0m, LegacyModule",
+	} {
+		if !strings.Contains(colored, expected) {
+			t.Errorf("Expected colored output to contain %q, got:\n%q", expected, colored)
+		}
+	}
+}
+
+func TestFormatter_Format_VerboseText_UsageReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		usage    analysis.ImportUsage
+		expected string
+	}{
+		{
+			name:     "Provider",
+			usage:    analysis.ImportUsage{Name: "MailerModule", Provider: "UsersService", Symbol: analysis.StringTokenKey("MAILER")},
+			expected: "MailerModule: used - UsersService uses 'MAILER', which it exports",
+		},
+		{
+			name:     "Provider definition",
+			usage:    analysis.ImportUsage{Name: "ConfigModule", Symbol: "ConfigService"},
+			expected: "ConfigModule: used - a provider definition of AppModule injects ConfigService, which it exports",
+		},
+		{
+			name:     "Not analyzed",
+			usage:    analysis.ImportUsage{Name: "UsersModule"},
+			expected: "UsersModule: used - its usage could not be analyzed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []*analysis.ModuleAnalysisResult{{
+				ModuleName:  "AppModule",
+				FilePath:    "src/app.module.ts",
+				UsedImports: []analysis.ImportUsage{tt.usage},
+			}}
+			output, err := reporting.NewFormatter().WithVerbose(true).Format(results, reporting.FormatText)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if !strings.Contains(output, "\t"+tt.expected+"\n") {
+				t.Errorf("Expected %q, got:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
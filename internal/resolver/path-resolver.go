@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -154,3 +155,62 @@ func (t *TsPathResolver) ResolveImportPath(importingFileDir, importPath string)
 	}
 	return filepath.Join(t.projectRoot, importPath)
 }
+
+// ImportSpecifier returns the module specifier fromFile should use to import
+// targetFile. A tsconfig paths alias mapping to the target is preferred, the
+// shortest one when several do; otherwise a relative path is returned.
+func (t *TsPathResolver) ImportSpecifier(fromFile, targetFile string) string {
+	target := strings.TrimSuffix(filepath.Clean(targetFile), ".ts")
+
+	aliases := make([]string, 0, len(t.paths))
+	for alias := range t.paths {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	best := ""
+	for _, alias := range aliases {
+		for _, path := range t.paths[alias] {
+			specifier, ok := matchAliasPath(alias, filepath.Join(t.projectRoot, path), target)
+			if ok && (best == "" || len(specifier) < len(best)) {
+				best = specifier
+			}
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	relativePath, err := filepath.Rel(filepath.Dir(fromFile), target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	relativePath = filepath.ToSlash(relativePath)
+	if !strings.HasPrefix(relativePath, "../") {
+		relativePath = "./" + relativePath
+	}
+	return relativePath
+}
+
+// matchAliasPath checks whether target, without extension, is covered by a
+// paths entry and returns the alias specifier for it
+func matchAliasPath(alias, path, target string) (string, bool) {
+	path = strings.TrimSuffix(filepath.Clean(path), ".ts")
+	if !strings.Contains(path, "*") {
+		if path == target && !strings.Contains(alias, "*") {
+			return alias, true
+		}
+		return "", false
+	}
+
+	wildcard := strings.Index(path, "*")
+	prefix, suffix := path[:wildcard], strings.TrimSuffix(path[wildcard+1:], ".ts")
+	if !strings.HasPrefix(target, prefix) || !strings.HasSuffix(target, suffix) || len(target) < len(prefix)+len(suffix) {
+		return "", false
+	}
+	captured := filepath.ToSlash(target[len(prefix) : len(target)-len(suffix)])
+	if !strings.Contains(alias, "*") {
+		return "", false
+	}
+	return strings.Replace(alias, "*", captured, 1), true
+}
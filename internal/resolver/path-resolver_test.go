@@ -39,3 +39,39 @@ func TestResolveImportPath(t *testing.T) {
 		})
 	}
 }
+
+func TestImportSpecifier(t *testing.T) {
+	tsConfig := `{
+		"compilerOptions": {
+			"paths": {
+				"@users/*": ["./src/users/*"],
+				"@config": ["./src/config/config.module.ts"]
+			}
+		}
+	}`
+
+	tests := []struct {
+		name       string
+		fromFile   string
+		targetFile string
+		expected   string
+	}{
+		{"wildcard alias", "/project/src/app.module.ts", "/project/src/users/users.module.ts", "@users/users.module"},
+		{"exact alias", "/project/src/app.module.ts", "/project/src/config/config.module.ts", "@config"},
+		{"relative sibling", "/project/src/orders/orders.module.ts", "/project/src/orders/items.module.ts", "./items.module"},
+		{"relative parent", "/project/src/orders/orders.module.ts", "/project/src/billing/billing.module.ts", "../billing/billing.module"},
+	}
+
+	tsPathResolver, err := resolver.NewTsPathResolver([]byte(tsConfig), "/project")
+	if err != nil {
+		t.Fatalf("Failed to create ts path resolver: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tsPathResolver.ImportSpecifier(tt.fromFile, tt.targetFile); got != tt.expected {
+				t.Errorf("ImportSpecifier() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}